@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/urfave/cli/v2"
+)
+
+// accountNameRegexp is the format of the account names, a name starts with a letter so that it is not mistaken for an address
+var accountNameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{0,63}$`)
+
+// validateAccountName check the format of the account name
+func validateAccountName(name string) error {
+	if !accountNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid account name %s, it should start with a letter and contain at most 64 letters, digits, '_' or '-'", name)
+	}
+	if _, err := sdk.AccAddressFromHexUnsafe(name); err == nil {
+		return fmt.Errorf("invalid account name %s, it should not be an address", name)
+	}
+	return nil
+}
+
+// loadAccountAliases read the names of the accounts in the home dir, the names are mapped to the addresses
+func loadAccountAliases(homeDir string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	content, err := os.ReadFile(filepath.Join(homeDir, DefaultAccountAliasPath))
+	if os.IsNotExist(err) {
+		return aliases, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the account names: %v", err)
+	}
+	if err = json.Unmarshal(content, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse the account names %s: %v", filepath.Join(homeDir, DefaultAccountAliasPath), err)
+	}
+	return aliases, nil
+}
+
+// writeAccountAliases replace the file of the account names
+func writeAccountAliases(homeDir string, aliases map[string]string) error {
+	aliasPath := filepath.Join(homeDir, DefaultAccountAliasPath)
+	content, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(aliasPath), 0700); err != nil {
+		return err
+	}
+	if err = writeKeyFileAtomic(aliasPath, content); err != nil {
+		return fmt.Errorf("failed to write the account names: %v", err)
+	}
+	return nil
+}
+
+// setAccountAlias name the account, the old name of the account is replaced
+func setAccountAlias(homeDir, name, address string) error {
+	aliases, err := loadAccountAliases(homeDir)
+	if err != nil {
+		return err
+	}
+	if owner, ok := aliases[name]; ok && convertAddressToLower(owner) != convertAddressToLower(address) {
+		return fmt.Errorf("the name %s is used by the account %s", name, owner)
+	}
+	removeAccountAliases(aliases, address)
+	aliases[name] = address
+	return writeAccountAliases(homeDir, aliases)
+}
+
+// removeAccountAliases remove the names of the address from the aliases
+func removeAccountAliases(aliases map[string]string, address string) {
+	for name, owner := range aliases {
+		if convertAddressToLower(owner) == convertAddressToLower(address) {
+			delete(aliases, name)
+		}
+	}
+}
+
+// getAccountAlias return the name of the address, an empty string is returned if it has no name
+func getAccountAlias(aliases map[string]string, address string) string {
+	for name, owner := range aliases {
+		if convertAddressToLower(owner) == convertAddressToLower(address) {
+			return name
+		}
+	}
+	return ""
+}
+
+// resolveAccountAddress return the address of the value, the value can be a hex address or the name of a local account
+func resolveAccountAddress(ctx *cli.Context, value string) (string, error) {
+	if _, err := sdk.AccAddressFromHexUnsafe(value); err == nil {
+		return value, nil
+	}
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	aliases, err := loadAccountAliases(homeDir)
+	if err != nil {
+		return "", err
+	}
+	if address, ok := aliases[value]; ok {
+		return address, nil
+	}
+	return "", fmt.Errorf("%s is neither an address nor the name of an account, see the names by \"gnfd-cmd account ls\"", value)
+}
+
+// getAddressFlag return the address of the flag which can be set to a hex address or the name of a local account,
+// an empty string is returned if the flag is not set
+func getAddressFlag(ctx *cli.Context, flagName string) (string, error) {
+	value := ctx.String(flagName)
+	if value == "" {
+		return "", nil
+	}
+	address, err := resolveAccountAddress(ctx, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid --%s: %v", flagName, err)
+	}
+	return address, nil
+}
+
+// getAddressListFlag return the addresses of the flag which is a comma separated list, every item can be a hex address
+// or the name of a local account, nil is returned if the flag is not set
+func getAddressListFlag(ctx *cli.Context, flagName string) ([]string, error) {
+	value := ctx.String(flagName)
+	if value == "" {
+		return nil, nil
+	}
+	items := strings.Split(value, ",")
+	addresses := make([]string, 0, len(items))
+	for _, item := range items {
+		address, err := resolveAccountAddress(ctx, strings.TrimSpace(item))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %v", flagName, err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	testAccountAddress      = "0x75345BC9FfFAe09486dE7EC954bAfAEcE29b9b24"
+	testOtherAccountAddress = "0x5a64aCD8DC6Ce41d824638419319409246A9b41A"
+)
+
+func TestValidateAccountName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "ops"},
+		{name: "ops-backup_2"},
+		{name: "O" + strings.Repeat("a", 63)},
+		{name: "", wantErr: true},
+		{name: "2ops", wantErr: true},
+		{name: "-ops", wantErr: true},
+		{name: "ops.backup", wantErr: true},
+		{name: "ops backup", wantErr: true},
+		{name: "O" + strings.Repeat("a", 64), wantErr: true},
+		{name: testAccountAddress, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateAccountName(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("got the error %v, expected an error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// writeTestAccounts write the keystores of the test accounts to the home dir, the first account is the default
+// account and is named ops, the other account is named backup
+func writeTestAccounts(t *testing.T) string {
+	homeDir := t.TempDir()
+	for _, address := range []string{testAccountAddress, testOtherAccountAddress} {
+		keyFilePath := filepath.Join(homeDir, DefaultKeyDir, "UTC--2024-01-01T00-00-00Z--"+convertAddressToLower(address))
+		if err := os.MkdirAll(filepath.Dir(keyFilePath), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(keyFilePath, []byte(`{"address":"`+address+`"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writeAccountAliases(homeDir, map[string]string{"ops": testAccountAddress, "backup": testOtherAccountAddress}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(homeDir, DefaultAccountPath), []byte(convertAddressToLower(testAccountAddress)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return homeDir
+}
+
+// runAccountApp run the app with the home flag, the address flags and the account commands
+func runAccountApp(homeDir string, action cli.ActionFunc, args ...string) error {
+	app := &cli.App{
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: homeFlag},
+			&cli.StringFlag{Name: toAddressFlag},
+			&cli.StringFlag{Name: addMemberFlag},
+		},
+		Commands:       []*cli.Command{cmdRenameAccount(), cmdRemoveAccount()},
+		Action:         action,
+		ExitErrHandler: func(_ *cli.Context, _ error) {},
+	}
+	return app.Run(append([]string{"gnfd-cmd", "--home", homeDir}, args...))
+}
+
+func TestResolveAccountAddress(t *testing.T) {
+	homeDir := writeTestAccounts(t)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "alias", value: "backup", want: testOtherAccountAddress},
+		{name: "address", value: testOtherAccountAddress, want: testOtherAccountAddress},
+		{name: "address without name", value: "0x0000000000000000000000000000000000000001", want: "0x0000000000000000000000000000000000000001"},
+		{name: "unknown", value: "unknown", wantErr: true},
+		{name: "alias of another case", value: "OPS", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got        string
+				resolveErr error
+			)
+			err := runAccountApp(homeDir, func(ctx *cli.Context) error {
+				got, resolveErr = resolveAccountAddress(ctx, tt.value)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr {
+				if resolveErr == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			}
+			if resolveErr != nil {
+				t.Fatalf("unexpected error: %v", resolveErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAddressListFlag(t *testing.T) {
+	homeDir := writeTestAccounts(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "not set"},
+		{name: "one name", args: []string{"--addMembers", "ops"}, want: []string{testAccountAddress}},
+		{
+			name: "names and addresses",
+			args: []string{"--addMembers", "backup, " + testAccountAddress + ",0x0000000000000000000000000000000000000001"},
+			want: []string{testOtherAccountAddress, testAccountAddress, "0x0000000000000000000000000000000000000001"},
+		},
+		{name: "unknown name", args: []string{"--addMembers", "ops,unknown"}, wantErr: true},
+		{name: "empty item", args: []string{"--addMembers", "ops,"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got     []string
+				flagErr error
+			)
+			err := runAccountApp(homeDir, func(ctx *cli.Context) error {
+				got, flagErr = getAddressListFlag(ctx, addMemberFlag)
+				return nil
+			}, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (flagErr != nil) != tt.wantErr {
+				t.Fatalf("got the error %v, expected an error %v", flagErr, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveAccount(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		// the names and the default account left after the command
+		wantAliases map[string]string
+		wantDefault string
+		wantRemoved bool
+	}{
+		{
+			name:        "default account without force",
+			args:        []string{"rm", "--yes", "ops"},
+			wantErr:     true,
+			wantAliases: map[string]string{"ops": testAccountAddress, "backup": testOtherAccountAddress},
+			wantDefault: testAccountAddress,
+		},
+		{
+			name:        "default account with force",
+			args:        []string{"rm", "--yes", "--force", "ops"},
+			wantAliases: map[string]string{"backup": testOtherAccountAddress},
+			wantRemoved: true,
+		},
+		{
+			name:        "another account by address",
+			args:        []string{"rm", "--yes", testOtherAccountAddress},
+			wantAliases: map[string]string{"ops": testAccountAddress},
+			wantDefault: testAccountAddress,
+			wantRemoved: true,
+		},
+		{
+			name:        "unknown account",
+			args:        []string{"rm", "--yes", "unknown"},
+			wantErr:     true,
+			wantAliases: map[string]string{"ops": testAccountAddress, "backup": testOtherAccountAddress},
+			wantDefault: testAccountAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := writeTestAccounts(t)
+			keyFilesBefore, _ := os.ReadDir(filepath.Join(homeDir, DefaultKeyDir))
+
+			err := runAccountApp(homeDir, nil, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got the error %v, expected an error %v", err, tt.wantErr)
+			}
+			checkAccountsOfHome(t, homeDir, tt.wantAliases, tt.wantDefault)
+			keyFilesAfter, _ := os.ReadDir(filepath.Join(homeDir, DefaultKeyDir))
+			if removed := len(keyFilesAfter) < len(keyFilesBefore); removed != tt.wantRemoved {
+				t.Errorf("got the keystore removed %v, expected %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestRenameAccount(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantErr     bool
+		wantAliases map[string]string
+	}{
+		{
+			name:        "rename by name",
+			args:        []string{"rename", "ops", "ops-new"},
+			wantAliases: map[string]string{"ops-new": testAccountAddress, "backup": testOtherAccountAddress},
+		},
+		{
+			name:        "rename by address",
+			args:        []string{"rename", testOtherAccountAddress, "cold"},
+			wantAliases: map[string]string{"ops": testAccountAddress, "cold": testOtherAccountAddress},
+		},
+		{
+			name:        "name of another account",
+			args:        []string{"rename", "ops", "backup"},
+			wantErr:     true,
+			wantAliases: map[string]string{"ops": testAccountAddress, "backup": testOtherAccountAddress},
+		},
+		{
+			name:        "invalid name",
+			args:        []string{"rename", "ops", "1ops"},
+			wantErr:     true,
+			wantAliases: map[string]string{"ops": testAccountAddress, "backup": testOtherAccountAddress},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := writeTestAccounts(t)
+			err := runAccountApp(homeDir, nil, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got the error %v, expected an error %v", err, tt.wantErr)
+			}
+			// the default account is not changed by the name
+			checkAccountsOfHome(t, homeDir, tt.wantAliases, testAccountAddress)
+		})
+	}
+}
+
+// checkAccountsOfHome check the names and the default account of the home dir, an empty default account means it is unset
+func checkAccountsOfHome(t *testing.T, homeDir string, wantAliases map[string]string, wantDefault string) {
+	aliases, err := loadAccountAliases(homeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(aliases, wantAliases) {
+		t.Errorf("got the names %v, expected %v", aliases, wantAliases)
+	}
+	content, err := os.ReadFile(filepath.Join(homeDir, DefaultAccountPath))
+	if wantDefault == "" {
+		if !os.IsNotExist(err) {
+			t.Errorf("got the default account %s, expected it is unset", content)
+		}
+		return
+	}
+	if string(content) != convertAddressToLower(wantDefault) {
+		t.Errorf("got the default account %s, expected %s", content, convertAddressToLower(wantDefault))
+	}
+}
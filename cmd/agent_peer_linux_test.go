@@ -0,0 +1,53 @@
+//go:build linux
+
+package main
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckAgentPeer(t *testing.T) {
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "peer.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		if conn, err := net.Dial("unix", listener.Addr().String()); err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err = checkAgentPeer(conn); err != nil {
+		t.Errorf("the peer of the same user is rejected: %v", err)
+	}
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	if err = checkAgentPeer(server); err == nil {
+		t.Errorf("the peer which is not a unix socket is accepted")
+	}
+}
+
+func TestKeyAgentRejectPeer(t *testing.T) {
+	agent, _, _ := startTestAgent(t, time.Minute)
+	client, server := net.Pipe()
+	defer client.Close()
+	go agent.handle(server)
+
+	// the agent closes the connection whose peer can not be checked without reading the request
+	_ = client.SetDeadline(time.Now().Add(time.Second))
+	response := make([]byte, 1024)
+	if n, err := client.Read(response); err == nil {
+		t.Errorf("got the response %q of a rejected peer", response[:n])
+	}
+}
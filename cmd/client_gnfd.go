@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/bnb-chain/greenfield-go-sdk/client"
 	"github.com/bnb-chain/greenfield-go-sdk/types"
 	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/urfave/cli/v2"
 )
 
@@ -28,7 +31,10 @@ $ gnfd-cmd version  `,
 }
 
 func showVersion(ctx *cli.Context) error {
-	fmt.Println("Greenfield Cmd Version:", Version)
+	if isStructuredOutput() {
+		return printRecord(map[string]string{"version": Version})
+	}
+	fmt.Fprintln(infoWriter, "Greenfield Cmd Version:", Version)
 	return nil
 }
 
@@ -42,15 +48,17 @@ func NewClient(ctx *cli.Context, opts ClientOptions) (client.IClient, error) {
 	)
 
 	if !opts.IsQueryCmd {
-		privateKey, _, err = parseKeystore(ctx)
-		if err != nil {
-			return nil, err
-		}
-
-		account, err = sdktypes.NewAccountFromPrivateKey("gnfd-account", privateKey)
-		if err != nil {
-			fmt.Println("new account err", err.Error())
-			return nil, err
+		// the agent signs for the command without asking for the password if it holds the key of the keystore
+		var ok bool
+		if account, ok = getAccountFromAgent(ctx); !ok {
+			privateKey, _, err = parseKeystore(ctx)
+			if err != nil {
+				return nil, newCmdError(exitCodeAuth, err)
+			}
+			account, err = sdktypes.NewAccountFromPrivateKey("gnfd-account", privateKey)
+			if err != nil {
+				return nil, newCmdError(exitCodeAuth, fmt.Errorf("new account err: %v", err))
+			}
 		}
 	}
 
@@ -66,8 +74,7 @@ func NewClient(ctx *cli.Context, opts ClientOptions) (client.IClient, error) {
 	}
 
 	if err != nil {
-		fmt.Printf("failed to create client %s \n", err.Error())
-		return nil, err
+		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 
 	return cli, nil
@@ -119,11 +126,135 @@ func waitTxnStatus(cli client.IClient, ctx context.Context, txnHash string, txnI
 
 	txnResponse, err := cli.WaitForTx(ctxTimeout, txnHash)
 	if err != nil {
-		return fmt.Errorf("the %s txn: %s ,has been submitted, please check it later:%v", txnInfo, txnHash, err)
+		return newCmdError(exitCodeTimeout, fmt.Errorf("the %s txn: %s ,has been submitted, please check it later:%v", txnInfo, txnHash, err))
 	}
 	if txnResponse.TxResult.Code != 0 {
-		return fmt.Errorf("the %s txn: %s has failed with response code: %d", txnInfo, txnHash, txnResponse.TxResult.Code)
+		return newCmdError(exitCodeTxFailed, fmt.Errorf("the %s txn: %s has failed with response code: %d", txnInfo, txnHash, txnResponse.TxResult.Code))
 	}
 
 	return nil
 }
+
+// failedMsgIndexRegexp matches the index of the failed msg in the log of a multi-msg txn
+var failedMsgIndexRegexp = regexp.MustCompile(`message index: (\d+)`)
+
+// parseFailedMsgIndex return the index of the failed msg from the txn log, or -1 if it is not found
+func parseFailedMsgIndex(log string) int {
+	matches := failedMsgIndexRegexp.FindStringSubmatch(log)
+	if len(matches) != 2 {
+		return -1
+	}
+	index, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+// broadcastMsgsAndWait send the msgs in one txn and wait for the txn to be committed.
+// If the txn fails because of one of the msgs, the index of that msg is returned, otherwise the index is -1.
+// The txn hash is empty if the txn has not been submitted to the chain. ErrTxnNotConfirmed is returned if waiting for
+// the submitted txn fails, the txn may still be committed later.
+func broadcastMsgsAndWait(cli client.IClient, ctx context.Context, msgs []sdk.Msg, txnInfo string) (string, int, error) {
+	resp, err := cli.BroadcastTx(ctx, msgs, &TxnOptionWithSyncMode)
+	if err != nil {
+		log := err.Error()
+		if resp != nil && resp.TxResponse != nil {
+			log = log + resp.TxResponse.RawLog
+		}
+		return "", parseFailedMsgIndex(log), fmt.Errorf("failed to broadcast the %s txn: %v", txnInfo, err)
+	}
+	txnHash := resp.TxResponse.TxHash
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, ContextTimeout)
+	defer cancel()
+
+	txnResponse, err := cli.WaitForTx(ctxTimeout, txnHash)
+	if err != nil {
+		return txnHash, -1, newCmdError(exitCodeTimeout, fmt.Errorf("%w: the %s txn: %s ,has been submitted, please check it later:%v",
+			ErrTxnNotConfirmed, txnInfo, txnHash, err))
+	}
+	if txnResponse.TxResult.Code != 0 {
+		return txnHash, parseFailedMsgIndex(txnResponse.TxResult.Log), newCmdError(exitCodeTxFailed,
+			fmt.Errorf("the %s txn: %s has failed with response code: %d, log: %s", txnInfo, txnHash, txnResponse.TxResult.Code, txnResponse.TxResult.Log))
+	}
+	return txnHash, -1, nil
+}
+
+// msgResult is the result of a msg group sent by broadcastMsgsInBatch
+type msgResult struct {
+	TxnHash string
+	Err     error
+}
+
+// broadcastMsgsInBatch send the msg groups by multi-msg txns, each txn contains at most batchSize groups.
+// The msgs of one group, such as createObject and setTag of the same object, are always sent in the same txn.
+// A txn is atomic, so if one msg of it fails, the group of the failed msg is excluded and the rest are sent again,
+// and if the failed msg is unknown, the groups are sent one by one. If the txn is not confirmed, the groups are not
+// sent again as the txn may still be committed, their error is ErrTxnNotConfirmed and the caller should check the chain.
+// It returns the txn hash or the error of each group in the same order as the groups.
+func broadcastMsgsInBatch(cli client.IClient, ctx context.Context, msgGroups [][]sdk.Msg, batchSize int, txnInfo string) []msgResult {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	results := make([]msgResult, len(msgGroups))
+	for start := 0; start < len(msgGroups); start += batchSize {
+		end := start + batchSize
+		if end > len(msgGroups) {
+			end = len(msgGroups)
+		}
+		pending := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			pending = append(pending, i)
+		}
+
+		for len(pending) > 0 {
+			// msgOwner records which group each msg of the txn belongs to
+			batch := make([]sdk.Msg, 0, len(pending))
+			msgOwner := make([]int, 0, len(pending))
+			for i, groupIndex := range pending {
+				batch = append(batch, msgGroups[groupIndex]...)
+				for range msgGroups[groupIndex] {
+					msgOwner = append(msgOwner, i)
+				}
+			}
+
+			txnHash, failedIndex, err := broadcastMsgsAndWait(cli, ctx, batch, txnInfo)
+			if err == nil {
+				for _, groupIndex := range pending {
+					results[groupIndex].TxnHash = txnHash
+				}
+				break
+			}
+
+			if errors.Is(err, ErrTxnNotConfirmed) {
+				for _, groupIndex := range pending {
+					results[groupIndex] = msgResult{TxnHash: txnHash, Err: err}
+				}
+				break
+			}
+
+			if failedIndex >= 0 && failedIndex < len(msgOwner) && len(pending) > 1 {
+				// exclude the group of the failed msg and send the rest again
+				failedGroup := msgOwner[failedIndex]
+				results[pending[failedGroup]].Err = err
+				pending = append(pending[:failedGroup], pending[failedGroup+1:]...)
+				continue
+			}
+
+			if len(pending) > 1 {
+				// the failed msg is unknown and nothing of the failed txn is committed, fall back to send the groups one by one
+				for _, groupIndex := range pending {
+					results[groupIndex] = broadcastMsgsInBatch(cli, ctx, msgGroups[groupIndex:groupIndex+1], 1, txnInfo)[0]
+				}
+				break
+			}
+
+			for _, groupIndex := range pending {
+				results[groupIndex] = msgResult{TxnHash: txnHash, Err: err}
+			}
+			break
+		}
+	}
+	return results
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/bnb-chain/greenfield-go-sdk/client"
+	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	"github.com/bnb-chain/greenfield/sdk/types"
+	storageTypes "github.com/bnb-chain/greenfield/x/storage/types"
+	abci "github.com/cometbft/cometbft/abci/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc"
+)
+
+// fakeGnfdClient is the client of the tests, the methods which are not set panic as the embedded client is nil
+type fakeGnfdClient struct {
+	client.IClient
+	broadcastTx      func(msgs []sdk.Msg) (*tx.BroadcastTxResponse, error)
+	waitForTx        func(hash string) (*ctypes.ResultTx, error)
+	computeHashRoots func(reader io.Reader) ([][]byte, int64, storageTypes.RedundancyType, error)
+	headObject       func(bucketName, objectName string) (*sdktypes.ObjectDetail, error)
+	getObject        func(bucketName, objectName string, opts sdktypes.GetObjectOptions) (io.ReadCloser, error)
+}
+
+func (f *fakeGnfdClient) BroadcastTx(_ context.Context, msgs []sdk.Msg, _ *types.TxOption, _ ...grpc.CallOption) (*tx.BroadcastTxResponse, error) {
+	return f.broadcastTx(msgs)
+}
+
+func (f *fakeGnfdClient) WaitForTx(_ context.Context, hash string) (*ctypes.ResultTx, error) {
+	return f.waitForTx(hash)
+}
+
+func (f *fakeGnfdClient) ComputeHashRoots(reader io.Reader, _ bool) ([][]byte, int64, storageTypes.RedundancyType, error) {
+	return f.computeHashRoots(reader)
+}
+
+func (f *fakeGnfdClient) HeadObject(_ context.Context, bucketName, objectName string) (*sdktypes.ObjectDetail, error) {
+	return f.headObject(bucketName, objectName)
+}
+
+func (f *fakeGnfdClient) GetObject(_ context.Context, bucketName, objectName string, opts sdktypes.GetObjectOptions) (io.ReadCloser, sdktypes.ObjectStat, error) {
+	body, err := f.getObject(bucketName, objectName, opts)
+	return body, sdktypes.ObjectStat{}, err
+}
+
+func TestParseFailedMsgIndex(t *testing.T) {
+	tests := []struct {
+		log  string
+		want int
+	}{
+		{log: "failed to execute message; message index: 0: No such object", want: 0},
+		{log: "failed to execute message; message index: 12: No such object", want: 12},
+		{log: "out of gas in location: ReadFlat; gasWanted: 1200, gasUsed: 1500", want: -1},
+		{log: "message index: x", want: -1},
+		{log: "", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.log, func(t *testing.T) {
+			if got := parseFailedMsgIndex(tt.log); got != tt.want {
+				t.Errorf("got %d, expected %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// newFakeChainClient return a client whose txns fail if they contain a msg to delete one of the failed objects.
+// If knownIndex is set, the log of the failed txn tells the index of the first failed msg. The object names of
+// the msgs of each txn are recorded in txns.
+func newFakeChainClient(failedObjects map[string]bool, knownIndex, notConfirmed bool, txns *[][]string) *fakeGnfdClient {
+	results := make(map[string]*ctypes.ResultTx)
+	return &fakeGnfdClient{
+		broadcastTx: func(msgs []sdk.Msg) (*tx.BroadcastTxResponse, error) {
+			hash := fmt.Sprintf("txn-%d", len(*txns))
+			names := make([]string, 0, len(msgs))
+			result := &ctypes.ResultTx{}
+			for i, msg := range msgs {
+				name := msg.(*storageTypes.MsgDeleteObject).ObjectName
+				names = append(names, name)
+				if failedObjects[name] && result.TxResult.Code == 0 {
+					result.TxResult = abci.ResponseDeliverTx{Code: 1, Log: "failed to execute message; No such object"}
+					if knownIndex {
+						result.TxResult.Log = fmt.Sprintf("failed to execute message; message index: %d: No such object", i)
+					}
+				}
+			}
+			*txns = append(*txns, names)
+			results[hash] = result
+			return &tx.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: hash}}, nil
+		},
+		waitForTx: func(hash string) (*ctypes.ResultTx, error) {
+			if notConfirmed {
+				return nil, context.DeadlineExceeded
+			}
+			return results[hash], nil
+		},
+	}
+}
+
+func TestBroadcastMsgsInBatch(t *testing.T) {
+	objectNames := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		name          string
+		batchSize     int
+		failedObjects map[string]bool
+		knownIndex    bool
+		notConfirmed  bool
+		wantTxns      [][]string
+		// wantErrs is the object names whose group fails
+		wantErrs map[string]bool
+	}{
+		{
+			name:      "all succeed",
+			batchSize: 2,
+			wantTxns:  [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+		},
+		{
+			name:          "exclude the failed msg of the known index",
+			batchSize:     5,
+			failedObjects: map[string]bool{"b": true, "d": true},
+			knownIndex:    true,
+			wantTxns:      [][]string{{"a", "b", "c", "d", "e"}, {"a", "c", "d", "e"}, {"a", "c", "e"}},
+			wantErrs:      map[string]bool{"b": true, "d": true},
+		},
+		{
+			name:          "send one by one if the failed msg is unknown",
+			batchSize:     3,
+			failedObjects: map[string]bool{"b": true},
+			wantTxns:      [][]string{{"a", "b", "c"}, {"a"}, {"b"}, {"c"}, {"d", "e"}},
+			wantErrs:      map[string]bool{"b": true},
+		},
+		{
+			name:         "not confirmed",
+			batchSize:    3,
+			notConfirmed: true,
+			wantTxns:     [][]string{{"a", "b", "c"}, {"d", "e"}},
+			wantErrs:     map[string]bool{"a": true, "b": true, "c": true, "d": true, "e": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var txns [][]string
+			gnfdClient := newFakeChainClient(tt.failedObjects, tt.knownIndex, tt.notConfirmed, &txns)
+			msgGroups := make([][]sdk.Msg, 0, len(objectNames))
+			for _, name := range objectNames {
+				msgGroups = append(msgGroups, []sdk.Msg{&storageTypes.MsgDeleteObject{BucketName: "bucket", ObjectName: name}})
+			}
+
+			results := broadcastMsgsInBatch(gnfdClient, context.Background(), msgGroups, tt.batchSize, "DeleteObject")
+			if !reflect.DeepEqual(txns, tt.wantTxns) {
+				t.Errorf("got the txns %v, expected %v", txns, tt.wantTxns)
+			}
+			for i, result := range results {
+				name := objectNames[i]
+				if (result.Err != nil) != tt.wantErrs[name] {
+					t.Errorf("got the error %v of %s, expected an error %v", result.Err, name, tt.wantErrs[name])
+				}
+				if tt.notConfirmed && !errors.Is(result.Err, ErrTxnNotConfirmed) {
+					t.Errorf("got the error %v of %s, expected ErrTxnNotConfirmed", result.Err, name)
+				}
+				if result.Err == nil && result.TxnHash == "" {
+					t.Errorf("the txn hash of %s is empty", name)
+				}
+			}
+		})
+	}
+}
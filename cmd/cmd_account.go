@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -15,6 +16,7 @@ import (
 	"github.com/bnb-chain/greenfield/sdk/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
 )
 
 // cmdImportAccount import the account by private key file
@@ -22,16 +24,70 @@ func cmdImportAccount() *cli.Command {
 	return &cli.Command{
 		Name:      "import",
 		Action:    importKey,
-		Usage:     "import the account by the private key file",
-		ArgsUsage: " <privateKeyFile>",
+		Usage:     "import the account by the private key file or the mnemonic",
+		ArgsUsage: " <privateKeyFile | mnemonicFile | web3KeystoreFile>",
 		Description: `
 Import account info from private key file and generate a keystore file to manage user's private key information.
 If no keyfile is specified by --keystore or -k flag, a keystore will be generated at the default path （homedir/.gnfd-cmd/keystore/key.json）
 Users need to set the private key file path which contain the origin private hex string .
 
+With --format web3-v3, the Web3 Secret Storage v3 file written by geth or MetaMask is imported, the password of the file
+is read from --passwordfile or the terminal and is also used to encrypt the imported keystore.
+
+With --mnemonic, the key is derived from the BIP-39 mnemonic by the derivation path. The mnemonic is read from the file
+if it is set, otherwise it is read from the terminal. The default derivation path is m/44'/60'/0'/0/INDEX, where INDEX is
+set by --accountIndex, so that multiple accounts can be derived from one mnemonic. Use --hdPath to set another path.
+
 Examples:
 // key.txt contains the origin private hex string 
-$ gnfd-cmd  account import  key.txt `,
+$ gnfd-cmd  account import  key.txt 
+$ gnfd-cmd  account import --name ops key.txt
+// import the second account of the mnemonic entered in the terminal
+$ gnfd-cmd  account import --mnemonic --accountIndex 1
+$ gnfd-cmd  account import --mnemonic --hdPath "m/44'/60'/1'/0/0" mnemonic.txt
+// encrypt the key with the light scrypt parameters on CI runners
+$ gnfd-cmd  account import --kdf light key.txt
+// import the keystore file of geth or MetaMask, the password of the file is also the password of the imported keystore
+$ gnfd-cmd  account import --format web3-v3 UTC--2023-08-01T08-00-00.000000000Z--5a64acd8dc6ce41d824638419319409246a9b41a`,
+		Flags: []cli.Flag{
+			&cli.GenericFlag{
+				Name: formatFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{hexKeyFormat, web3V3KeyFormat},
+					Default: hexKeyFormat,
+				},
+				Usage: "the format of the key file, hex for the private hex string, or web3-v3 for the Web3 Secret Storage v3 file of geth and MetaMask",
+			},
+			&cli.StringFlag{
+				Name:  accountNameFlag,
+				Value: "",
+				Usage: "the name of the account, it can be used instead of the address in --keystore, set-default and the address flags",
+			},
+			&cli.BoolFlag{
+				Name:  mnemonicFlag,
+				Value: false,
+				Usage: "import the account derived from the BIP-39 mnemonic",
+			},
+			&cli.StringFlag{
+				Name:  hdPathFlag,
+				Value: defaultHDPath,
+				Usage: "the BIP-32 derivation path of the key, only used with --mnemonic",
+			},
+			&cli.UintFlag{
+				Name:  accountIndexFlag,
+				Value: 0,
+				Usage: "the index of the account derived by the path m/44'/60'/0'/0/INDEX, only used with --mnemonic",
+			},
+			&cli.GenericFlag{
+				Name: kdfFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{standardKdf, lightKdf},
+					Default: standardKdf,
+				},
+				Usage: "set the scrypt parameters of encrypting the key, standard or light. The light kdf is faster but weaker against brute force, " +
+					"it is suitable for the CI runners",
+			},
+		},
 	}
 }
 
@@ -56,10 +112,37 @@ func cmdCreateAccount() *cli.Command {
 		Usage:     "create a new account",
 		ArgsUsage: "",
 		Description: `
-create a new account and store the private key in a keystore file
+create a new account and store the private key in a keystore file.
+With --name, the account can be referred to by the name instead of the address.
+With --mnemonic, the key is derived from a new BIP-39 mnemonic of 24 words by the path m/44'/60'/0'/0/0,
+the mnemonic is shown only once and is not stored, it can recover the account by "account import --mnemonic".
 
 Examples:
-$ gnfd-cmd account new  `,
+$ gnfd-cmd account new  
+$ gnfd-cmd account new --name ops
+$ gnfd-cmd account new --mnemonic
+$ gnfd-cmd account new --kdf light`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  mnemonicFlag,
+				Value: false,
+				Usage: "create the account from a new BIP-39 mnemonic, the mnemonic is shown only once",
+			},
+			&cli.StringFlag{
+				Name:  accountNameFlag,
+				Value: "",
+				Usage: "the name of the account, it can be used instead of the address in --keystore, set-default and the address flags",
+			},
+			&cli.GenericFlag{
+				Name: kdfFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{standardKdf, lightKdf},
+					Default: standardKdf,
+				},
+				Usage: "set the scrypt parameters of encrypting the key, standard or light. The light kdf is faster but weaker against brute force, " +
+					"it is suitable for the CI runners",
+			},
+		},
 	}
 }
 
@@ -75,9 +158,26 @@ When both the --unarmored-hex and --unsafe flags are selected, cryptographic
 private key material is exported in an INSECURE fashion that is designed to
 allow users to import their keys in hot wallets. 
 
+With --format web3-v3, the key is exported as a Web3 Secret Storage v3 file which can be read by geth and other wallets,
+the file is encrypted with the password of the keystore. The file is printed to stdout unless --exportFile is set.
+
 Examples:
-$ gnfd-cmd account export --unarmoredHex --unsafe`,
+$ gnfd-cmd account export --unarmoredHex --unsafe
+$ gnfd-cmd account export --format web3-v3 --exportFile key.json`,
 		Flags: []cli.Flag{
+			&cli.GenericFlag{
+				Name: formatFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{armoredKeyFormat, web3V3KeyFormat},
+					Default: armoredKeyFormat,
+				},
+				Usage: "the format of the exported key, armored for the cipher text of the keystore, or web3-v3 for the Web3 Secret Storage v3 file",
+			},
+			&cli.StringFlag{
+				Name:  exportFileFlag,
+				Value: "",
+				Usage: "the path of the exported web3-v3 file, the file is printed to stdout if it is not set",
+			},
 			&cli.BoolFlag{
 				Name:  unsafeFlag,
 				Usage: "indicate export private key in plain text",
@@ -90,6 +190,41 @@ $ gnfd-cmd account export --unarmoredHex --unsafe`,
 	}
 }
 
+// cmdChangePassword change the password of the keystore
+func cmdChangePassword() *cli.Command {
+	return &cli.Command{
+		Name:      "passwd",
+		Action:    changePassword,
+		Usage:     "change the password of the keystore",
+		ArgsUsage: "",
+		Description: `
+Decrypt the keystore with the old password and encrypt it with the new password. The keystore is the default account,
+the account of the profile in use or the keystore set by --keystore. The old password is read from --passwordfile or the terminal,
+and the new password is read from --newPasswordFile or the terminal.
+The keystore file is replaced atomically, so it is either encrypted with the old password or the new password if the command is interrupted.
+The scrypt parameters of the keystore are kept unless --kdf is set.
+
+Examples:
+$ gnfd-cmd account passwd
+$ gnfd-cmd --passwordfile old.txt --keystore key.json account passwd --newPasswordFile new.txt --kdf light`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  newPasswordFileFlag,
+				Value: "",
+				Usage: "the file of the new password, the new password is read from the terminal if it is not set",
+			},
+			&cli.GenericFlag{
+				Name: kdfFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{standardKdf, lightKdf},
+					Default: standardKdf,
+				},
+				Usage: "set the scrypt parameters of encrypting the key, standard or light, the parameters of the keystore are kept if it is not set",
+			},
+		},
+	}
+}
+
 func cmdGetAccountBalance() *cli.Command {
 	return &cli.Command{
 		Name:      "balance",
@@ -119,12 +254,68 @@ func cmdSetDefaultAccount() *cli.Command {
 		ArgsUsage: " ",
 		Description: `
 Set the default account value. When running other commands, the keystore corresponding to this account will be used by default.
+The account can be the address or the name of the account.
+
+Examples:
+$ gnfd-cmd account set-default  0x75345BC9FfFAe09486dE7EC954bAfAEcE29b9b24
+$ gnfd-cmd account set-default  ops`,
+	}
+}
+
+func cmdRenameAccount() *cli.Command {
+	return &cli.Command{
+		Name:      "rename",
+		Action:    renameAccount,
+		Usage:     "set the name of the account",
+		ArgsUsage: "<address | name> <newName>",
+		Description: `
+Set the name of the account in the keystore, the old name of the account is replaced.
+The name can be used instead of the address in --keystore, set-default and the address flags like --toAddress.
+It starts with a letter and contains at most 64 letters, digits, '_' or '-'.
 
 Examples:
-$ gnfd-cmd account default  0x75345BC9FfFAe09486dE7EC954bAfAEcE29b9b24`,
+$ gnfd-cmd account rename 0x75345BC9FfFAe09486dE7EC954bAfAEcE29b9b24 ops
+$ gnfd-cmd account rename ops ops-backup`,
 	}
 }
 
+func cmdRemoveAccount() *cli.Command {
+	return &cli.Command{
+		Name:      "rm",
+		Action:    removeAccount,
+		Usage:     "remove the account from the keystore",
+		ArgsUsage: "<address | name>",
+		Description: `
+Remove the keystore file and the name of the account. The removal is confirmed in the terminal unless --yes is set,
+the key can not be recovered after it is removed unless it has been backed up.
+The default account is not removed unless --force is set, then there is no default account until set-default is run.
+
+Examples:
+$ gnfd-cmd account rm ops
+$ gnfd-cmd account rm --yes --force 0x75345BC9FfFAe09486dE7EC954bAfAEcE29b9b24`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    yesFlag,
+				Aliases: []string{"y"},
+				Value:   false,
+				Usage:   "remove the account without the confirmation",
+			},
+			&cli.BoolFlag{
+				Name:  forceFlag,
+				Value: false,
+				Usage: "remove the account even if it is the default account",
+			},
+		},
+	}
+}
+
+// balanceRecord is the record of the balance queried by "bank balance"
+type balanceRecord struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+	Denom   string `json:"denom"`
+}
+
 func getAccountBalance(ctx *cli.Context) error {
 	client, err := NewClient(ctx, ClientOptions{IsQueryCmd: true})
 	if err != nil {
@@ -143,7 +334,10 @@ func getAccountBalance(ctx *cli.Context) error {
 	if err != nil {
 		return toCmdErr(err)
 	}
-	fmt.Printf("balance: %s wei%s\n", resp.Amount.String(), types.Denom)
+	if isStructuredOutput() {
+		return printRecord(balanceRecord{Address: addr, Amount: resp.Amount.String(), Denom: resp.Denom})
+	}
+	fmt.Fprintf(infoWriter, "balance: %s wei%s\n", resp.Amount.String(), types.Denom)
 	return nil
 }
 
@@ -207,74 +401,144 @@ $ gnfd-cmd bank bridge --toAddress 0x.. --amount 12345`,
 
 func importKey(ctx *cli.Context) error {
 	var (
-		err               error
-		homeDir, password string
-		encryptContent    []byte
+		key *Key
+		err error
 	)
-	privateKeyFile := ctx.Args().First()
-	if privateKeyFile == "" {
-		return toCmdErr(errors.New("fail to get the private key file info"))
+	if ctx.Bool(mnemonicFlag) {
+		if ctx.String(formatFlag) != hexKeyFormat {
+			return toCmdErr(fmt.Errorf("the flag %s can not be used with %s", mnemonicFlag, formatFlag))
+		}
+		key, err = loadKeyFromMnemonic(ctx)
+		if err != nil {
+			return toCmdErr(err)
+		}
+	} else if ctx.String(formatFlag) == web3V3KeyFormat {
+		key, err = loadWeb3Keystore(ctx)
+		if err != nil {
+			return toCmdErr(err)
+		}
+	} else {
+		privateKeyFile := ctx.Args().First()
+		if privateKeyFile == "" {
+			return toCmdErr(errors.New("fail to get the private key file info"))
+		}
+
+		// Load private key from file.
+		privateKey, addr, err := loadKey(privateKeyFile)
+		if err != nil {
+			return toCmdErr(errors.New("failed to load private key: %v" + err.Error()))
+		}
+		key = &Key{
+			Address:    addr,
+			PrivateKey: privateKey,
+		}
 	}
 
-	// Load private key from file.
-	privateKey, addr, err := loadKey(privateKeyFile)
+	homeDir, keyFilePath, err := storeKey(ctx, key)
 	if err != nil {
-		return toCmdErr(errors.New("failed to load private key: %v" + err.Error()))
+		return toCmdErr(err)
+	}
+
+	if isStructuredOutput() {
+		return printRecord(accountRecord{Address: key.Address.String(), Name: ctx.String(accountNameFlag), Keystore: keyFilePath,
+			Default: isDefaultAccount(homeDir, key.Address.String())})
 	}
+	fmt.Fprintf(infoWriter, "imported account: %s, keystore: %s \n", key.Address, keyFilePath)
+	return nil
+}
 
-	homeDir, err = getHomeDir(ctx)
+// loadWeb3Keystore decrypt the Web3 Secret Storage v3 file of the first arg, the password of the file is also
+// used to encrypt the imported keystore
+func loadWeb3Keystore(ctx *cli.Context) (*Key, error) {
+	keystoreFile := ctx.Args().First()
+	if keystoreFile == "" {
+		return nil, errors.New("fail to get the web3 keystore file info")
+	}
+	keyJson, err := os.ReadFile(keystoreFile)
 	if err != nil {
-		return toCmdErr(err)
+		return nil, fmt.Errorf("failed to read the web3 keystore: %v", err)
 	}
+	password, err := getPassword(ctx, false)
+	if err != nil {
+		return nil, newCmdError(exitCodeAuth, err)
+	}
+	key, err := decryptWeb3Keystore(keyJson, password)
+	if err != nil {
+		return nil, newCmdError(exitCodeAuth, err)
+	}
+	return key, nil
+}
 
-	if isKeystoreExist(homeDir+"/"+DefaultKeyDir, addr.String()) {
-		fmt.Println("account already exists")
-		return nil
+// storeKey encrypt the key with the password and store it in the keystore file, the keystore file is set by
+// the keystore flag or generated under the home dir. The home dir and the path of the keystore file are returned.
+func storeKey(ctx *cli.Context, key *Key) (string, string, error) {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	if isKeystoreExist(homeDir+"/"+DefaultKeyDir, key.Address.String()) {
+		return "", "", fmt.Errorf("account %s already exists", key.Address.String())
+	}
+
+	// check the name before asking for the password
+	name := ctx.String(accountNameFlag)
+	if name != "" {
+		if err = validateAccountName(name); err != nil {
+			return "", "", err
+		}
+		aliases, err := loadAccountAliases(homeDir)
+		if err != nil {
+			return "", "", err
+		}
+		if owner, ok := aliases[name]; ok {
+			return "", "", fmt.Errorf("the name %s is used by the account %s", name, owner)
+		}
 	}
 
 	keyFilePath := ctx.String("keystore")
 	if keyFilePath == "" {
 		utcTimestamp := time.Now().UTC().Format(timeFormat)
-		keyFilePath = filepath.Join(homeDir, DefaultKeyDir+"/"+utcTimestamp+"--"+convertAddressToLower(addr.String()))
+		keyFilePath = filepath.Join(homeDir, DefaultKeyDir+"/"+utcTimestamp+"--"+convertAddressToLower(key.Address.String()))
 	}
 
 	if _, err = os.Stat(keyFilePath); err == nil {
-		return toCmdErr(errors.New("key already exists at :" + keyFilePath))
+		return "", "", errors.New("key already exists at :" + keyFilePath)
 	} else if !os.IsNotExist(err) {
-		return toCmdErr(err)
-	}
-
-	key := &Key{
-		Address:    addr,
-		PrivateKey: privateKey,
+		return "", "", err
 	}
 
 	// fetch password content
-	password, err = getPassword(ctx, true)
+	password, err := getPassword(ctx, true)
 	if err != nil {
-		return toCmdErr(err)
+		return "", "", err
 	}
 
 	// encrypt the private key
-	encryptContent, err = EncryptKey(key, password, EncryptScryptN, EncryptScryptP)
+	scryptN, scryptP := getScryptParams(ctx.String(kdfFlag))
+	encryptContent, err := EncryptKey(key, password, scryptN, scryptP)
 	if err != nil {
-		return toCmdErr(err)
+		return "", "", err
 	}
 
 	if err = os.MkdirAll(filepath.Dir(keyFilePath), 0700); err != nil {
-		return toCmdErr(errors.New("failed to create directory %s" + filepath.Dir(keyFilePath)))
+		return "", "", errors.New("failed to create directory %s" + filepath.Dir(keyFilePath))
 	}
 
 	// store the keystore file
 	if err = os.WriteFile(keyFilePath, encryptContent, 0600); err != nil {
-		return toCmdErr(fmt.Errorf("failed to write keyfile to the path%s: %v", keyFilePath, err))
+		return "", "", fmt.Errorf("failed to write keyfile to the path%s: %v", keyFilePath, err)
+	}
+
+	if name != "" {
+		if err = setAccountAlias(homeDir, name, key.Address.String()); err != nil {
+			return "", "", err
+		}
 	}
 
 	// if it is the first keystore, set it as the default key
 	checkAndWriteDefaultKey(homeDir, convertAddressToLower(key.Address.String()))
-
-	fmt.Printf("imported account: %s, keystore: %s \n", key.Address, keyFilePath)
-	return nil
+	return homeDir, keyFilePath, nil
 }
 
 func listAccounts(ctx *cli.Context) error {
@@ -294,21 +558,52 @@ func listAccounts(ctx *cli.Context) error {
 		defaultAccount = string(fileContent)
 	}
 
-	if err = listKeyStore(keyfileDir, defaultAccount); err != nil {
+	aliases, err := loadAccountAliases(homeDir)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	if err = listKeyStore(keyfileDir, defaultAccount, aliases); err != nil {
 		return toCmdErr(err)
 	}
 
 	return nil
 }
 
-func listKeyStore(keystoreDir, defaultAccount string) error {
+// accountRecord is the record of the accounts listed by "account ls"
+type accountRecord struct {
+	Address  string `json:"address"`
+	Name     string `json:"name,omitempty"`
+	Keystore string `json:"keystore,omitempty"`
+	Default  bool   `json:"default"`
+	// Mnemonic is only printed by "account new --mnemonic"
+	Mnemonic string `json:"mnemonic,omitempty"`
+}
+
+// exportRecord is the record of the key exported by "account export"
+type exportRecord struct {
+	Address    string `json:"address,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"`
+	ArmoredKey string `json:"armored_key,omitempty"`
+	File       string `json:"file,omitempty"`
+}
+
+// isDefaultAccount check if the address is the default account
+func isDefaultAccount(homeDir, address string) bool {
+	content, err := os.ReadFile(filepath.Join(homeDir, DefaultAccountPath))
+	return err == nil && string(content) == convertAddressToLower(address)
+}
+
+func listKeyStore(keystoreDir, defaultAccount string, aliases map[string]string) error {
 	var (
 		keyFileContent []byte
 		err            error
 	)
 	files, err := os.ReadDir(keystoreDir)
-	if err != nil {
-		return errors.New("keystore not exists")
+	if os.IsNotExist(err) {
+		return newCmdError(exitCodeNotFound, errors.New("keystore not exists"))
+	} else if err != nil {
+		return newCmdError(exitCodeGeneral, fmt.Errorf("failed to read the keystore dir %s: %v", keystoreDir, err))
 	}
 
 	for _, file := range files {
@@ -328,10 +623,22 @@ func listKeyStore(keystoreDir, defaultAccount string) error {
 				return toCmdErr(err)
 			}
 
-			if defaultAccount != "" && convertAddressToLower(k.Address) == defaultAccount {
-				fmt.Printf("Account: { %s },  Keystore : %s (default account)\n", k.Address, keyPath)
+			isDefault := defaultAccount != "" && convertAddressToLower(k.Address) == defaultAccount
+			name := getAccountAlias(aliases, k.Address)
+			if isStructuredOutput() {
+				if err = printRecord(accountRecord{Address: k.Address, Name: name, Keystore: keyPath, Default: isDefault}); err != nil {
+					return err
+				}
+				continue
+			}
+			account := k.Address
+			if name != "" {
+				account = name + ": " + k.Address
+			}
+			if isDefault {
+				fmt.Fprintf(infoWriter, "Account: { %s },  Keystore : %s (default account)\n", account, keyPath)
 			} else {
-				fmt.Printf("Account: { %s },  Keystore : %s \n", k.Address, keyPath)
+				fmt.Fprintf(infoWriter, "Account: { %s },  Keystore : %s \n", account, keyPath)
 			}
 		}
 	}
@@ -342,12 +649,22 @@ func exportAccount(ctx *cli.Context) error {
 	unsafe := ctx.Bool(unsafeFlag)
 	unarmored := ctx.Bool(unarmoredFlag)
 
+	if ctx.String(formatFlag) == web3V3KeyFormat {
+		if unarmored || unsafe {
+			return toCmdErr(fmt.Errorf("the flag %s can not be used with %s and %s", formatFlag, unsafeFlag, unarmoredFlag))
+		}
+		return exportWeb3Keystore(ctx)
+	}
+
 	if unarmored && unsafe {
 		privateKey, _, err := parseKeystore(ctx)
 		if err != nil {
 			return toCmdErr(err)
 		}
-		fmt.Println("Private key: ", privateKey)
+		if isStructuredOutput() {
+			return printRecord(exportRecord{PrivateKey: privateKey})
+		}
+		fmt.Fprintln(infoWriter, "Private key: ", privateKey)
 		return nil
 	} else if unarmored || unsafe {
 		return fmt.Errorf("the flags %s and %s must be used together", unsafeFlag, unarmoredFlag)
@@ -363,71 +680,116 @@ func exportAccount(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Println("Armored key: ", keyJson.Crypto.CipherText)
+	if isStructuredOutput() {
+		return printRecord(exportRecord{Address: keyJson.Address, ArmoredKey: keyJson.Crypto.CipherText})
+	}
+	fmt.Fprintln(infoWriter, "Armored key: ", keyJson.Crypto.CipherText)
 
 	return nil
 }
 
-func createAccount(ctx *cli.Context) error {
-	var (
-		err            error
-		homeDir        string
-		password       string
-		encryptContent []byte
-	)
-
-	account, privateKey, err := sdktypes.NewAccount("gnfd-account")
+// exportWeb3Keystore export the key as a Web3 Secret Storage v3 file encrypted with the password of the keystore
+func exportWeb3Keystore(ctx *cli.Context) error {
+	keyJson, _, err := loadKeyStoreFile(ctx)
 	if err != nil {
 		return toCmdErr(err)
 	}
-
-	key := &Key{
-		Address:    account.GetAddress(),
-		PrivateKey: privateKey,
+	encrypted := new(encryptedKey)
+	if err = json.Unmarshal(keyJson, encrypted); err != nil {
+		return toCmdErr(err)
+	}
+	password, err := getPassword(ctx, false)
+	if err != nil {
+		return newCmdError(exitCodeAuth, err)
+	}
+	privateKey, err := DecryptKey(keyJson, password)
+	if err != nil {
+		return newCmdError(exitCodeAuth, fmt.Errorf("failed to decrypting key: %v", err))
+	}
+	address, err := sdk.AccAddressFromHexUnsafe(encrypted.Address)
+	if err != nil {
+		return newCmdError(exitCodeAuth, fmt.Errorf("failed to parse the address of the keystore: %v", err))
 	}
 
-	homeDir, err = getHomeDir(ctx)
+	scryptN, scryptP := getKeyScryptParams(encrypted)
+	content, err := encryptWeb3Keystore(&Key{Address: address, PrivateKey: privateKey}, password, scryptN, scryptP)
 	if err != nil {
 		return toCmdErr(err)
 	}
 
-	keyFilePath := ctx.String("keystore")
-	if keyFilePath == "" {
-		utcTimestamp := time.Now().UTC().Format(timeFormat)
-		keyFilePath = filepath.Join(homeDir, DefaultKeyDir+"/"+utcTimestamp+"--"+convertAddressToLower(account.GetAddress().String()))
+	exportFile := ctx.String(exportFileFlag)
+	if exportFile == "" {
+		_, err = fmt.Fprintln(resultWriter, string(content))
+		return err
 	}
-
-	if _, err = os.Stat(keyFilePath); err == nil {
-		fmt.Println("key already exists at :", keyFilePath)
-		return nil
-	} else if !os.IsNotExist(err) {
+	if err = writeNewFile(exportFile, content); err != nil {
 		return toCmdErr(err)
 	}
+	if isStructuredOutput() {
+		return printRecord(exportRecord{Address: address.String(), File: exportFile})
+	}
+	fmt.Fprintf(infoWriter, "exported account %s to the web3 keystore %s \n", address.String(), exportFile)
+	return nil
+}
 
-	// fetch password content
-	password, err = getPassword(ctx, true)
-	if err != nil {
-		return toCmdErr(err)
+// writeNewFile write the content to a file which must not exist, the file is created exclusively so that a file
+// created by others after the check is never overwritten
+func writeNewFile(filePath string, content []byte) error {
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return fmt.Errorf("the file %s already exists", filePath)
+	} else if err != nil {
+		return fmt.Errorf("failed to create the file %s: %v", filePath, err)
+	}
+	if _, err = file.Write(content); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write the file %s: %v", filePath, err)
 	}
+	return file.Close()
+}
 
-	// encrypt the private key
-	encryptContent, err = EncryptKey(key, password, EncryptScryptN, EncryptScryptP)
+func createAccount(ctx *cli.Context) error {
+	var (
+		key      *Key
+		mnemonic string
+		err      error
+	)
+	if ctx.Bool(mnemonicFlag) {
+		mnemonic, err = newMnemonic()
+		if err != nil {
+			return toCmdErr(err)
+		}
+		key, err = deriveKeyFromMnemonic(mnemonic, defaultHDPath)
+		if err != nil {
+			return toCmdErr(err)
+		}
+	} else {
+		account, privateKey, err := sdktypes.NewAccount("gnfd-account")
+		if err != nil {
+			return toCmdErr(err)
+		}
+		key = &Key{
+			Address:    account.GetAddress(),
+			PrivateKey: privateKey,
+		}
+	}
+
+	homeDir, keyFilePath, err := storeKey(ctx, key)
 	if err != nil {
 		return toCmdErr(err)
 	}
 
-	if err = os.MkdirAll(filepath.Dir(keyFilePath), 0700); err != nil {
-		return toCmdErr(errors.New("failed to create directory %s" + filepath.Dir(keyFilePath)))
+	if isStructuredOutput() {
+		return printRecord(accountRecord{Address: key.Address.String(), Name: ctx.String(accountNameFlag), Keystore: keyFilePath,
+			Default: isDefaultAccount(homeDir, key.Address.String()), Mnemonic: mnemonic})
 	}
-	// store the keystore file
-	if err = os.WriteFile(keyFilePath, encryptContent, 0600); err != nil {
-		return toCmdErr(fmt.Errorf("failed to write keyfile to the path%s: %v", keyFilePath, err))
+	fmt.Fprintf(infoWriter, "created new account: {%s}, keystore: %s \n", key.Address, keyFilePath)
+	if mnemonic != "" {
+		// the mnemonic is not stored anywhere, it is only shown once
+		fmt.Fprintln(infoWriter, "- The recovery phrase of the account is shown ONLY ONCE, write it down and keep it in a safe place!")
+		fmt.Fprintln(infoWriter, "- Anyone who has the recovery phrase can take control of the account.")
+		fmt.Fprintf(infoWriter, "\n%s\n\n", mnemonic)
 	}
-
-	// if it is the first keystore, set it as the default key
-	checkAndWriteDefaultKey(homeDir, convertAddressToLower(key.Address.String()))
-
-	fmt.Printf("created new account: {%s}, keystore: %s \n", account.GetAddress(), keyFilePath)
 	return nil
 }
 
@@ -440,7 +802,10 @@ func Bridge(ctx *cli.Context) error {
 	c, transfer := context.WithCancel(globalContext)
 	defer transfer()
 
-	toAddr := ctx.String(toAddressFlag)
+	toAddr, err := getAddressFlag(ctx, toAddressFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	_, err = sdk.AccAddressFromHexUnsafe(toAddr)
 	if err != nil {
 		return toCmdErr(err)
@@ -460,7 +825,10 @@ func Bridge(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Printf("transfer out %s BNB to %s succ, txHash: %s\n", amountStr, toAddr, txResp.TxHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "bridge", Resource: toAddr, Amount: amountStr, TxnHash: txResp.TxHash})
+	}
+	fmt.Fprintf(infoWriter, "transfer out %s BNB to %s succ, txHash: %s\n", amountStr, toAddr, txResp.TxHash)
 	return nil
 }
 
@@ -473,7 +841,10 @@ func Transfer(ctx *cli.Context) error {
 	c, transfer := context.WithCancel(globalContext)
 	defer transfer()
 
-	toAddr := ctx.String(toAddressFlag)
+	toAddr, err := getAddressFlag(ctx, toAddressFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	_, err = sdk.AccAddressFromHexUnsafe(toAddr)
 	if err != nil {
 		return toCmdErr(err)
@@ -492,7 +863,10 @@ func Transfer(ctx *cli.Context) error {
 	if err != nil {
 		return toCmdErr(err)
 	}
-	fmt.Printf("transfer %s BNB to address %s succ, txHash: %s\n", amountStr, toAddr, txHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "transfer", Resource: toAddr, Amount: amountStr, TxnHash: txHash})
+	}
+	fmt.Fprintf(infoWriter, "transfer %s BNB to address %s succ, txHash: %s\n", amountStr, toAddr, txHash)
 	return nil
 }
 
@@ -501,8 +875,7 @@ func setDefaultAccount(ctx *cli.Context) error {
 		return toCmdErr(fmt.Errorf("args number error"))
 	}
 
-	defaultAddress := ctx.Args().Get(0)
-	_, err := sdk.AccAddressFromHexUnsafe(defaultAddress)
+	defaultAddress, err := resolveAccountAddress(ctx, ctx.Args().Get(0))
 	if err != nil {
 		return toCmdErr(errors.New("failed to set the default account:" + err.Error()))
 	}
@@ -528,7 +901,10 @@ func setDefaultAccount(ctx *cli.Context) error {
 		return toCmdErr(errors.New("failed to set the default account:" + err.Error()))
 	}
 
-	fmt.Println("the default account has been set to", defaultAddress)
+	if isStructuredOutput() {
+		return printRecord(accountRecord{Address: defaultAddress, Default: true})
+	}
+	fmt.Fprintln(infoWriter, "the default account has been set to", defaultAddress)
 	return nil
 }
 
@@ -540,12 +916,12 @@ func parseKeystore(ctx *cli.Context) (string, string, error) {
 	// fetch password content
 	password, err := getPassword(ctx, false)
 	if err != nil {
-		return "", "", toCmdErr(err)
+		return "", "", newCmdError(exitCodeAuth, err)
 	}
 
 	privateKey, err := DecryptKey(keyjson, password)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to decrypting key: %v \n", err)
+		return "", "", newCmdError(exitCodeAuth, fmt.Errorf("failed to decrypting key: %v \n", err))
 	}
 
 	return privateKey, keyFile, nil
@@ -557,26 +933,26 @@ func checkAndWriteDefaultKey(homeDir string, content string) {
 	_, err = os.Stat(filePath)
 	if os.IsNotExist(err) {
 		if err = os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
-			fmt.Printf("failed to create directory %s, error: %v\n", filepath.Dir(filePath), err)
+			fmt.Fprintf(infoWriter, "failed to create directory %s, error: %v\n", filepath.Dir(filePath), err)
 		}
 
 		err = os.WriteFile(filePath, []byte(content), 0644)
 		if err != nil {
-			fmt.Printf("failed to write default keystore info %v \n", err)
+			fmt.Fprintf(infoWriter, "failed to write default keystore info %v \n", err)
 			return
 		}
 	} else {
 		// file exist, check if it is empty
 		fileContent, readErr := os.ReadFile(filePath)
 		if readErr != nil {
-			fmt.Printf("read default keystore info fail %v \n", readErr)
+			fmt.Fprintf(infoWriter, "read default keystore info fail %v \n", readErr)
 			return
 		}
 
 		if len(fileContent) == 0 {
 			err = os.WriteFile(filePath, []byte(content), 0644)
 			if err != nil {
-				fmt.Printf("failed to write default keystore info %v \n", err)
+				fmt.Fprintf(infoWriter, "failed to write default keystore info %v \n", err)
 			}
 		}
 	}
@@ -603,3 +979,208 @@ func isKeystoreExist(keystoreDir string, address string) bool {
 	}
 	return false
 }
+
+// getNewPassword read the new password from the file of newPasswordFile flag or the terminal
+func getNewPassword(ctx *cli.Context) (string, error) {
+	if passwordFile := ctx.String(newPasswordFileFlag); passwordFile != "" {
+		content, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return "", errors.New("failed to read new password file" + err.Error())
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, "Please enter the new passphrase now:")
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read password err: %v", err)
+	}
+	fmt.Fprint(os.Stderr, "Please repeat the new passphrase:")
+	repeated, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read password err: %v", err)
+	}
+	if string(password) != string(repeated) {
+		return "", errors.New("the new passphrases do not match")
+	}
+	return string(password), nil
+}
+
+func changePassword(ctx *cli.Context) error {
+	keyJson, keyFilePath, err := loadKeyStoreFile(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	encrypted := new(encryptedKey)
+	if err = json.Unmarshal(keyJson, encrypted); err != nil {
+		return newCmdError(exitCodeAuth, fmt.Errorf("failed to parse the keystore %s: %v", keyFilePath, err))
+	}
+
+	password, err := getPassword(ctx, false)
+	if err != nil {
+		return newCmdError(exitCodeAuth, err)
+	}
+	privateKey, err := DecryptKey(keyJson, password)
+	if err != nil {
+		return newCmdError(exitCodeAuth, fmt.Errorf("failed to decrypting key: %v", err))
+	}
+
+	newPassword, err := getNewPassword(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if newPassword == "" {
+		return toCmdErr(errors.New("the new password should not be empty"))
+	}
+
+	address, err := sdk.AccAddressFromHexUnsafe(encrypted.Address)
+	if err != nil {
+		return newCmdError(exitCodeAuth, fmt.Errorf("failed to parse the address of the keystore: %v", err))
+	}
+	scryptN, scryptP := getKeyScryptParams(encrypted)
+	if ctx.IsSet(kdfFlag) {
+		scryptN, scryptP = getScryptParams(ctx.String(kdfFlag))
+	}
+	encryptContent, err := EncryptKey(&Key{Address: address, PrivateKey: privateKey}, newPassword, scryptN, scryptP)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if err = writeKeyFileAtomic(keyFilePath, encryptContent); err != nil {
+		return toCmdErr(fmt.Errorf("failed to write keyfile to the path %s: %v", keyFilePath, err))
+	}
+	// the agent holding the key must not keep serving it with the old password
+	stopAgentOfKeystore(ctx, keyFilePath)
+
+	if isStructuredOutput() {
+		homeDir, err := getHomeDir(ctx)
+		if err != nil {
+			return toCmdErr(err)
+		}
+		return printRecord(accountRecord{Address: address.String(), Keystore: keyFilePath, Default: isDefaultAccount(homeDir, address.String())})
+	}
+	fmt.Fprintf(infoWriter, "the password of account %s is changed, keystore: %s \n", address.String(), keyFilePath)
+	fmt.Fprintln(infoWriter, "- You must REMEMBER your new password! Without the password, it's impossible to decrypt the key!")
+	return nil
+}
+
+// findAccountKeystore return the address and the keystore file of the account which is an address or a name
+func findAccountKeystore(ctx *cli.Context, account string) (string, string, error) {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	address, err := resolveAccountAddress(ctx, account)
+	if err != nil {
+		return "", "", err
+	}
+	keyStoreDir := filepath.Join(homeDir, DefaultKeyDir)
+	keyFilePath, err := getKeystoreFileByAddress(keyStoreDir, convertAddressToLower(address))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find the keystore of %s: %v", account, err)
+	}
+	if keyFilePath == "" {
+		return "", "", newCmdError(exitCodeNotFound, fmt.Errorf("the keystore of the account %s does not exist in %s", address, keyStoreDir))
+	}
+	return address, keyFilePath, nil
+}
+
+func renameAccount(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return newCmdError(exitCodeUsage, fmt.Errorf("args number error, the account and the new name should be set"))
+	}
+	name := ctx.Args().Get(1)
+	if err := validateAccountName(name); err != nil {
+		return newCmdError(exitCodeUsage, err)
+	}
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	address, keyFilePath, err := findAccountKeystore(ctx, ctx.Args().Get(0))
+	if err != nil {
+		return toCmdErr(err)
+	}
+	// the address of the keystore is used so that the names of the same account always have the same form
+	keyJson, err := os.ReadFile(keyFilePath)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	k := new(encryptedKey)
+	if err = json.Unmarshal(keyJson, k); err == nil && k.Address != "" {
+		address = k.Address
+	}
+
+	if err = setAccountAlias(homeDir, name, address); err != nil {
+		return toCmdErr(err)
+	}
+	if isStructuredOutput() {
+		return printRecord(accountRecord{Address: address, Name: name, Keystore: keyFilePath, Default: isDefaultAccount(homeDir, address)})
+	}
+	fmt.Fprintf(infoWriter, "the name of the account %s has been set to %s\n", address, name)
+	return nil
+}
+
+func removeAccount(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return newCmdError(exitCodeUsage, fmt.Errorf("args number error, the account should be set"))
+	}
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	address, keyFilePath, err := findAccountKeystore(ctx, ctx.Args().First())
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	isDefault := isDefaultAccount(homeDir, address)
+	if isDefault && !ctx.Bool(forceFlag) {
+		return newCmdError(exitCodeUsage, fmt.Errorf("the account %s is the default account, set another default account "+
+			"by \"gnfd-cmd account set-default\" or set --%s to remove it", address, forceFlag))
+	}
+
+	if !ctx.Bool(yesFlag) {
+		// print the prompt to stderr, so that it will not be mixed with the records written to stdout
+		fmt.Fprintf(os.Stderr, "The keystore %s will be removed, the key of the account %s can not be recovered "+
+			"unless it has been backed up. Remove it? [y/N]: ", keyFilePath, address)
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			fmt.Fprintln(os.Stderr)
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			return toCmdErr(errors.New("the removal of the account is not confirmed"))
+		}
+	}
+
+	if err = os.Remove(keyFilePath); err != nil {
+		return newCmdError(exitCodeGeneral, fmt.Errorf("failed to remove the keystore %s: %v", keyFilePath, err))
+	}
+	stopAgentOfKeystore(ctx, keyFilePath)
+	aliases, err := loadAccountAliases(homeDir)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if getAccountAlias(aliases, address) != "" {
+		removeAccountAliases(aliases, address)
+		if err = writeAccountAliases(homeDir, aliases); err != nil {
+			return toCmdErr(err)
+		}
+	}
+	if isDefault {
+		if err = os.Remove(filepath.Join(homeDir, DefaultAccountPath)); err != nil && !os.IsNotExist(err) {
+			return toCmdErr(fmt.Errorf("failed to unset the default account: %v", err))
+		}
+	}
+
+	if isStructuredOutput() {
+		return printRecord(accountRecord{Address: address, Keystore: keyFilePath, Default: isDefault})
+	}
+	fmt.Fprintf(infoWriter, "removed account %s, keystore: %s\n", address, keyFilePath)
+	if isDefault {
+		fmt.Fprintln(infoWriter, "there is no default account now, set it by \"gnfd-cmd account set-default\"")
+	}
+	return nil
+}
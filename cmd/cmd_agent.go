@@ -0,0 +1,497 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	"github.com/bnb-chain/greenfield/sdk/keys"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/eth/ethsecp256k1"
+	ctypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// DefaultAgentSocketPath is the unix socket of the agent in the home dir, the dir is only accessible by the user
+	DefaultAgentSocketPath = "agent/agent.sock"
+	defaultAgentTTL        = 15 * time.Minute
+	agentDialTimeout       = time.Second
+	agentConnTimeout       = 5 * time.Second
+
+	agentSignOp   = "sign"
+	agentStatusOp = "status"
+	agentStopOp   = "stop"
+)
+
+// agentRequest is the request sent to the agent, one json line per connection
+type agentRequest struct {
+	Op string `json:"op"`
+	// KeyFile is the absolute path of the keystore resolved by the client, the data is only signed if it matches
+	KeyFile string `json:"keyFile,omitempty"`
+	// Data is the bytes to be signed by the sign operation
+	Data []byte `json:"data,omitempty"`
+}
+
+// agentResponse is the response of the agent, the private key never leaves the agent
+type agentResponse struct {
+	Address   string `json:"address,omitempty"`
+	KeyFile   string `json:"keyFile,omitempty"`
+	ExpireAt  int64  `json:"expireAt,omitempty"`
+	PublicKey []byte `json:"publicKey,omitempty"`
+	Signature []byte `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// agentRecord is the record printed by "agent start" and "agent status"
+type agentRecord struct {
+	Address  string `json:"address"`
+	Keystore string `json:"keystore"`
+	Socket   string `json:"socket"`
+	ExpireAt string `json:"expireAt"`
+}
+
+// keyAgent holds the decrypted key of one keystore in memory and signs for the commands until the ttl expires
+type keyAgent struct {
+	address  string
+	keyFile  string
+	km       keys.KeyManager
+	expireAt time.Time
+	// keyDigest is the hash of the keystore, the agent stops once the keystore is changed or removed
+	keyDigest [sha256.Size]byte
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// agentKeyManager is the key manager of the commands which signs with the key held by the agent
+type agentKeyManager struct {
+	socketPath string
+	keyFile    string
+	address    sdk.AccAddress
+	pubKey     *ethsecp256k1.PubKey
+}
+
+func cmdStartAgent() *cli.Command {
+	return &cli.Command{
+		Name:      "start",
+		Action:    startAgent,
+		Usage:     "decrypt the keystore once and sign for the commands with the key held in memory",
+		ArgsUsage: "",
+		Description: `
+Decrypt the keystore with the password and sign for the commands over a unix socket in the home dir until the ttl
+expires, the agent is stopped by "agent stop", Ctrl+C, the end of the ttl, or once the keystore is changed by
+"account passwd" or removed by "account rm". The commands sending transactions or requests to the storage providers
+sign with the agent instead of asking for the password if the agent holds the key of their keystore.
+The agent runs in the foreground, start it in another terminal, or in the background with --passwordfile.
+
+The private key never leaves the agent. The socket and its dir are only accessible by the user and the agent rejects
+the connections of the other users on Linux, the peer is not checked on the other platforms. Any process of the user
+can ask the agent to sign while it is running, so stop it when it is no longer needed.
+
+Examples:
+$ gnfd-cmd agent start --ttl 1h
+$ gnfd-cmd --passwordfile password.txt agent start --ttl 30m &`,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  ttlFlag,
+				Value: defaultAgentTTL,
+				Usage: "how long the key is held in memory, such as 30m or 2h",
+			},
+		},
+	}
+}
+
+func cmdStopAgent() *cli.Command {
+	return &cli.Command{
+		Name:      "stop",
+		Action:    stopAgent,
+		Usage:     "stop the agent and drop the key",
+		ArgsUsage: "",
+		Description: `
+Stop the running agent of the home dir, the key held by the agent is dropped.
+
+Examples:
+$ gnfd-cmd agent stop`,
+	}
+}
+
+func cmdAgentStatus() *cli.Command {
+	return &cli.Command{
+		Name:      "status",
+		Action:    showAgentStatus,
+		Usage:     "show the account held by the agent and when it expires",
+		ArgsUsage: "",
+		Description: `
+Show the account and the keystore held by the running agent of the home dir and when the key expires.
+
+Examples:
+$ gnfd-cmd agent status`,
+	}
+}
+
+func startAgent(ctx *cli.Context) error {
+	ttl := ctx.Duration(ttlFlag)
+	if ttl <= 0 {
+		return newCmdError(exitCodeUsage, fmt.Errorf("the %s should be greater than 0", ttlFlag))
+	}
+	socketPath, err := getAgentSocketPath(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	privateKey, keyFile, err := parseKeystore(ctx)
+	if err != nil {
+		return newCmdError(exitCodeAuth, err)
+	}
+	keyFile, err = filepath.Abs(keyFile)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	keyDigest, err := digestKeystore(keyFile)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	km, err := keys.NewPrivateKeyManager(privateKey)
+	if err != nil {
+		return newCmdError(exitCodeAuth, fmt.Errorf("new account err: %v", err))
+	}
+	address := km.GetAddr().String()
+
+	if err = os.MkdirAll(filepath.Dir(socketPath), 0700); err != nil {
+		return toCmdErr(err)
+	}
+	if err = os.Chmod(filepath.Dir(socketPath), 0700); err != nil {
+		return toCmdErr(err)
+	}
+	// check the running agent after the slow decryption, so that two agents started together do not both listen
+	if _, err = callAgent(socketPath, agentRequest{Op: agentStatusOp}); err == nil {
+		return toCmdErr(fmt.Errorf("an agent is already running on %s, stop it with \"gnfd-cmd agent stop\" first", socketPath))
+	}
+	// the socket left by an agent which is killed can not be listened on again
+	if err = os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return toCmdErr(err)
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return toCmdErr(fmt.Errorf("failed to listen on %s: %v", socketPath, err))
+	}
+	defer listener.Close()
+	if err = os.Chmod(socketPath, 0600); err != nil {
+		return toCmdErr(err)
+	}
+	// only remove the socket on exit if it has not been replaced by another agent
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	socketInfo, err := os.Stat(socketPath)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	defer func() {
+		if info, err := os.Stat(socketPath); err == nil && os.SameFile(info, socketInfo) {
+			os.Remove(socketPath)
+		}
+	}()
+
+	agent := &keyAgent{
+		address:   address,
+		keyFile:   keyFile,
+		km:        km,
+		expireAt:  time.Now().Add(ttl),
+		keyDigest: keyDigest,
+		stop:      make(chan struct{}),
+	}
+	record := agentRecord{Address: address, Keystore: keyFile, Socket: socketPath, ExpireAt: formatRecordTime(agent.expireAt.Unix())}
+	if isStructuredOutput() {
+		if err = printRecord(record); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(infoWriter, "agent started for %s, the key expires at %s\n", address, formatTime(agent.expireAt.Unix()))
+	}
+
+	if !agentPeerChecked {
+		fmt.Fprintln(os.Stderr, "the peer of the agent is not checked on this platform, only the permissions of the socket protect the agent")
+	}
+	go agent.serve(listener)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	timer := time.NewTimer(ttl)
+	defer timer.Stop()
+
+	select {
+	case <-agent.stop:
+		if agent.keystoreChanged() {
+			fmt.Fprintln(os.Stderr, "agent stopped, the keystore has been changed or removed")
+			return nil
+		}
+		fmt.Fprintln(os.Stderr, "agent stopped")
+	case <-timer.C:
+		fmt.Fprintln(os.Stderr, "agent expired")
+	case <-signals:
+		fmt.Fprintln(os.Stderr, "agent interrupted")
+	}
+	return nil
+}
+
+func stopAgent(ctx *cli.Context) error {
+	socketPath, err := getAgentSocketPath(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if _, err = callAgent(socketPath, agentRequest{Op: agentStopOp}); err != nil {
+		return toCmdErr(err)
+	}
+	if isStructuredOutput() {
+		return printRecord(map[string]string{"socket": socketPath, "status": "stopped"})
+	}
+	fmt.Fprintln(infoWriter, "agent stopped")
+	return nil
+}
+
+func showAgentStatus(ctx *cli.Context) error {
+	socketPath, err := getAgentSocketPath(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	resp, err := callAgent(socketPath, agentRequest{Op: agentStatusOp})
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if isStructuredOutput() {
+		return printRecord(agentRecord{Address: resp.Address, Keystore: resp.KeyFile, Socket: socketPath,
+			ExpireAt: formatRecordTime(resp.ExpireAt)})
+	}
+	fmt.Fprintln(infoWriter, "address:", resp.Address)
+	fmt.Fprintln(infoWriter, "keystore:", resp.KeyFile)
+	fmt.Fprintln(infoWriter, "socket:", socketPath)
+	fmt.Fprintln(infoWriter, "expire at:", formatTime(resp.ExpireAt))
+	return nil
+}
+
+// serve handle the connections until the listener is closed
+func (a *keyAgent) serve(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go a.handle(conn)
+	}
+}
+
+func (a *keyAgent) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(agentConnTimeout))
+
+	// the connections of the other users are closed without reading the request
+	if err := checkAgentPeer(conn); err != nil {
+		return
+	}
+
+	var req agentRequest
+	var resp agentResponse
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &req)
+	}
+	switch {
+	case err != nil:
+		resp.Error = fmt.Sprintf("invalid request: %v", err)
+	case time.Now().After(a.expireAt):
+		resp.Error = "the key of the agent has expired"
+	case (req.Op == agentStatusOp || req.Op == agentSignOp) && a.keystoreChanged():
+		resp.Error = fmt.Sprintf("the keystore %s has been changed or removed, the agent is stopped", a.keyFile)
+		defer a.stopOnce.Do(func() { close(a.stop) })
+	case req.Op == agentStatusOp:
+		resp = agentResponse{Address: a.address, KeyFile: a.keyFile, ExpireAt: a.expireAt.Unix(), PublicKey: a.km.PubKey().Bytes()}
+	case req.Op == agentSignOp:
+		if req.KeyFile != a.keyFile {
+			resp.Error = fmt.Sprintf("the agent holds the key of %s", a.keyFile)
+			break
+		}
+		signature, err := a.km.Sign(req.Data)
+		if err != nil {
+			resp.Error = fmt.Sprintf("failed to sign: %v", err)
+			break
+		}
+		resp = agentResponse{Address: a.address, KeyFile: a.keyFile, Signature: signature}
+	case req.Op == agentStopOp:
+		resp = agentResponse{Address: a.address, KeyFile: a.keyFile}
+		defer a.stopOnce.Do(func() { close(a.stop) })
+	default:
+		resp.Error = fmt.Sprintf("unknown operation %s", req.Op)
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+// getAgentSocketPath return the socket of the agent in the home dir
+func getAgentSocketPath(ctx *cli.Context) (string, error) {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, DefaultAgentSocketPath), nil
+}
+
+// callAgent send the request to the agent of the socket and return its response
+func callAgent(socketPath string, req agentRequest) (*agentResponse, error) {
+	conn, err := net.DialTimeout("unix", socketPath, agentDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("no agent is running on %s", socketPath)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(agentConnTimeout))
+
+	if err = json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send the request to the agent: %v", err)
+	}
+	resp := new(agentResponse)
+	if err = json.NewDecoder(conn).Decode(resp); err != nil {
+		return nil, fmt.Errorf("failed to read the response of the agent: %v", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// keystoreChanged check whether the keystore held by the agent has been re-encrypted or removed
+func (a *keyAgent) keystoreChanged() bool {
+	digest, err := digestKeystore(a.keyFile)
+	return err != nil || !bytes.Equal(digest[:], a.keyDigest[:])
+}
+
+// digestKeystore return the hash of the content of the keystore
+func digestKeystore(keyFile string) ([sha256.Size]byte, error) {
+	content, err := os.ReadFile(keyFile)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(content), nil
+}
+
+// getAccountFromAgent return the account which signs with the running agent, false is returned if there is no agent
+// or the agent holds the key of another keystore, then the keystore should be decrypted with the password
+func getAccountFromAgent(ctx *cli.Context) (*sdktypes.Account, bool) {
+	socketPath, err := getAgentSocketPath(ctx)
+	if err != nil {
+		return nil, false
+	}
+	if _, err = os.Stat(socketPath); err != nil {
+		return nil, false
+	}
+	_, keyFile, err := loadKeyStoreFile(ctx)
+	if err != nil {
+		return nil, false
+	}
+	if keyFile, err = filepath.Abs(keyFile); err != nil {
+		return nil, false
+	}
+	resp, err := callAgent(socketPath, agentRequest{Op: agentStatusOp})
+	if err != nil || resp.KeyFile != keyFile {
+		return nil, false
+	}
+	address, err := sdk.AccAddressFromHexUnsafe(resp.Address)
+	if err != nil {
+		return nil, false
+	}
+	km := &agentKeyManager{
+		socketPath: socketPath,
+		keyFile:    keyFile,
+		address:    address,
+		pubKey:     &ethsecp256k1.PubKey{Key: resp.PublicKey},
+	}
+	if !bytes.Equal(km.pubKey.Address(), address) {
+		return nil, false
+	}
+	account, err := newAgentAccount(km)
+	if err != nil {
+		// the agent holds the key but can not sign with this build, tell the user why the password is asked for
+		fmt.Fprintf(os.Stderr, "the agent is not used: %v\n", err)
+		return nil, false
+	}
+	return account, true
+}
+
+// newAgentAccount create the account of the key manager. The sdk only creates the accounts from the raw keys, so the
+// key manager is set to the unexported field of the account which holds it, the field is found by its type and the
+// account is checked to return the key manager, an error is returned if the layout of the account has changed.
+// It depends on the layout of the account of the pinned sdk version, TestAgentAccountLayout fails once it changes.
+func newAgentAccount(km keys.KeyManager) (*sdktypes.Account, error) {
+	account := new(sdktypes.Account)
+	value := reflect.ValueOf(account).Elem()
+	kmType := reflect.TypeOf((*keys.KeyManager)(nil)).Elem()
+	var field reflect.Value
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).Type != kmType {
+			continue
+		}
+		if field.IsValid() {
+			return nil, errors.New("the account of the sdk has more than one key manager")
+		}
+		field = value.Field(i)
+	}
+	if !field.IsValid() {
+		return nil, errors.New("the account of the sdk has no key manager")
+	}
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Set(reflect.ValueOf(km))
+	if account.GetKeyManager() != km {
+		return nil, errors.New("the account of the sdk does not use the key manager of the agent")
+	}
+	return account, nil
+}
+
+// stopAgentOfKeystore stop the running agent if it holds the key of the keystore
+func stopAgentOfKeystore(ctx *cli.Context, keyFile string) {
+	socketPath, err := getAgentSocketPath(ctx)
+	if err != nil {
+		return
+	}
+	if keyFile, err = filepath.Abs(keyFile); err != nil {
+		return
+	}
+	if resp, err := callAgent(socketPath, agentRequest{Op: agentStatusOp}); err != nil || resp.KeyFile != keyFile {
+		return
+	}
+	_, _ = callAgent(socketPath, agentRequest{Op: agentStopOp})
+}
+
+// Bytes return nothing as the private key is only held by the agent
+func (k *agentKeyManager) Bytes() []byte { return nil }
+
+// Sign send the bytes to the agent to be signed
+func (k *agentKeyManager) Sign(msg []byte) ([]byte, error) {
+	resp, err := callAgent(k.socketPath, agentRequest{Op: agentSignOp, KeyFile: k.keyFile, Data: msg})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with the agent: %v", err)
+	}
+	return resp.Signature, nil
+}
+
+func (k *agentKeyManager) PubKey() ctypes.PubKey { return k.pubKey }
+
+func (k *agentKeyManager) Equals(key ctypes.LedgerPrivKey) bool { return k.pubKey.Equals(key.PubKey()) }
+
+func (k *agentKeyManager) Type() string { return k.pubKey.Type() }
+
+func (k *agentKeyManager) GetAddr() sdk.AccAddress { return k.address }
+
+func (k *agentKeyManager) String() string { return k.address.String() }
+
+func (k *agentKeyManager) ProtoMessage() {}
+
+func (k *agentKeyManager) Reset() {}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	"github.com/bnb-chain/greenfield/sdk/keys"
+	"github.com/urfave/cli/v2"
+)
+
+const testAgentPrivateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+// startTestAgent serve an agent on a socket of the home dir until the test ends, the keystore only needs to exist
+// as the agent holds the decrypted key
+func startTestAgent(t *testing.T, ttl time.Duration) (*keyAgent, string, string) {
+	homeDir := t.TempDir()
+	keyFile := filepath.Join(homeDir, "keystore", "key.json")
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, []byte(`{"address":"test"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keyDigest, err := digestKeystore(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	km, err := keys.NewPrivateKeyManager(testAgentPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	socketPath := filepath.Join(homeDir, DefaultAgentSocketPath)
+	if err = os.MkdirAll(filepath.Dir(socketPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	agent := &keyAgent{
+		address:   km.GetAddr().String(),
+		keyFile:   keyFile,
+		km:        km,
+		expireAt:  time.Now().Add(ttl),
+		keyDigest: keyDigest,
+		stop:      make(chan struct{}),
+	}
+	go agent.serve(listener)
+	return agent, homeDir, socketPath
+}
+
+// waitAgentStopped wait for the agent to be stopped, the agent stops after it has responded to the request
+func waitAgentStopped(agent *keyAgent, timeout time.Duration) bool {
+	select {
+	case <-agent.stop:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func TestKeyAgentHandle(t *testing.T) {
+	data := []byte("the bytes to be signed")
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		// prepare change the state of the agent before the request, the request is built by the keystore
+		prepare     func(t *testing.T, agent *keyAgent)
+		req         func(agent *keyAgent) agentRequest
+		wantErr     string
+		wantStopped bool
+	}{
+		{
+			name: "status",
+			ttl:  time.Minute,
+			req:  func(agent *keyAgent) agentRequest { return agentRequest{Op: agentStatusOp} },
+		},
+		{
+			name: "sign",
+			ttl:  time.Minute,
+			req: func(agent *keyAgent) agentRequest {
+				return agentRequest{Op: agentSignOp, KeyFile: agent.keyFile, Data: data}
+			},
+		},
+		{
+			name: "sign for another keystore",
+			ttl:  time.Minute,
+			req: func(agent *keyAgent) agentRequest {
+				return agentRequest{Op: agentSignOp, KeyFile: agent.keyFile + ".other", Data: data}
+			},
+			wantErr: "the agent holds the key of",
+		},
+		{
+			name: "expired",
+			ttl:  -time.Second,
+			req: func(agent *keyAgent) agentRequest {
+				return agentRequest{Op: agentSignOp, KeyFile: agent.keyFile, Data: data}
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "keystore changed",
+			ttl:  time.Minute,
+			prepare: func(t *testing.T, agent *keyAgent) {
+				if err := os.WriteFile(agent.keyFile, []byte(`{"address":"changed"}`), 0600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			req: func(agent *keyAgent) agentRequest {
+				return agentRequest{Op: agentSignOp, KeyFile: agent.keyFile, Data: data}
+			},
+			wantErr:     "has been changed or removed",
+			wantStopped: true,
+		},
+		{
+			name: "keystore removed",
+			ttl:  time.Minute,
+			prepare: func(t *testing.T, agent *keyAgent) {
+				if err := os.Remove(agent.keyFile); err != nil {
+					t.Fatal(err)
+				}
+			},
+			req:         func(agent *keyAgent) agentRequest { return agentRequest{Op: agentStatusOp} },
+			wantErr:     "has been changed or removed",
+			wantStopped: true,
+		},
+		{
+			name:        "stop",
+			ttl:         time.Minute,
+			req:         func(agent *keyAgent) agentRequest { return agentRequest{Op: agentStopOp} },
+			wantStopped: true,
+		},
+		{
+			name:    "unknown operation",
+			ttl:     time.Minute,
+			req:     func(agent *keyAgent) agentRequest { return agentRequest{Op: "export"} },
+			wantErr: "unknown operation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, _, socketPath := startTestAgent(t, tt.ttl)
+			if tt.prepare != nil {
+				tt.prepare(t, agent)
+			}
+			req := tt.req(agent)
+
+			resp, err := callAgent(socketPath, req)
+			timeout := 10 * time.Millisecond
+			if tt.wantStopped {
+				timeout = time.Second
+			}
+			if stopped := waitAgentStopped(agent, timeout); stopped != tt.wantStopped {
+				t.Errorf("got the agent stopped %v, expected %v", stopped, tt.wantStopped)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got the error %v, expected %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Address != agent.address || resp.KeyFile != agent.keyFile {
+				t.Errorf("got the key (%s, %s), expected (%s, %s)", resp.Address, resp.KeyFile, agent.address, agent.keyFile)
+			}
+			switch req.Op {
+			case agentStatusOp:
+				if !bytes.Equal(resp.PublicKey, agent.km.PubKey().Bytes()) || resp.ExpireAt != agent.expireAt.Unix() {
+					t.Errorf("got the status %+v, expected the public key and the expire time of the agent", resp)
+				}
+			case agentSignOp:
+				if !agent.km.PubKey().VerifySignature(data, resp.Signature) {
+					t.Errorf("the signature of the agent is not valid")
+				}
+			}
+		})
+	}
+}
+
+func TestKeyAgentInvalidRequest(t *testing.T) {
+	_, _, socketPath := startTestAgent(t, time.Minute)
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = conn.Write([]byte("not json\n")); err != nil {
+		t.Fatal(err)
+	}
+	response := make([]byte, 1024)
+	n, _ := conn.Read(response)
+	if !strings.Contains(string(response[:n]), "invalid request") {
+		t.Errorf("got the response %q, expected an invalid request error", response[:n])
+	}
+}
+
+func TestGetAccountFromAgent(t *testing.T) {
+	agent, homeDir, _ := startTestAgent(t, time.Minute)
+
+	tests := []struct {
+		name     string
+		keystore string
+		wantOk   bool
+	}{
+		{name: "keystore of the agent", keystore: agent.keyFile, wantOk: true},
+		{name: "another keystore", keystore: filepath.Join(homeDir, "keystore", "other.json")},
+	}
+	if err := os.WriteFile(tests[1].keystore, []byte(`{"address":"other"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &cli.App{
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: homeFlag},
+					&cli.StringFlag{Name: keyStoreFlag},
+				},
+				Action: func(ctx *cli.Context) error {
+					account, ok := getAccountFromAgent(ctx)
+					if ok != tt.wantOk {
+						t.Fatalf("got ok %v, expected %v", ok, tt.wantOk)
+					}
+					if !ok {
+						return nil
+					}
+					if account.GetAddress().String() != agent.address {
+						t.Errorf("got the address %s, expected %s", account.GetAddress().String(), agent.address)
+					}
+					data := []byte("the bytes to be signed")
+					signature, err := account.Sign(data)
+					if err != nil {
+						t.Fatalf("failed to sign with the agent: %v", err)
+					}
+					if !agent.km.PubKey().VerifySignature(data, signature) {
+						t.Errorf("the signature of the account is not valid")
+					}
+					return nil
+				},
+			}
+			if err := app.Run([]string{"gnfd-cmd", "--home", homeDir, "--keystore", tt.keystore}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestNewAgentAccount fail once the account of the sdk no longer holds the key manager in a field of its own,
+// then the commands would silently stop using the agent
+func TestNewAgentAccount(t *testing.T) {
+	km, err := keys.NewPrivateKeyManager(testAgentPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := newAgentAccount(km)
+	if err != nil {
+		t.Fatalf("failed to create the account of the agent: %v", err)
+	}
+	if account.GetKeyManager() != km || !account.GetAddress().Equals(km.GetAddr()) {
+		t.Errorf("the account does not use the key manager of the agent")
+	}
+}
+
+// TestAgentAccountLayout fail loudly once the account of the sdk is changed, newAgentAccount sets the unexported key
+// manager of the account, so the sdk should not be bumped before the agent is checked with the new layout
+func TestAgentAccountLayout(t *testing.T) {
+	want := []struct {
+		name string
+		typ  reflect.Type
+	}{
+		{name: "name", typ: reflect.TypeOf("")},
+		{name: "km", typ: reflect.TypeOf((*keys.KeyManager)(nil)).Elem()},
+	}
+	accountType := reflect.TypeOf(sdktypes.Account{})
+	if accountType.NumField() != len(want) {
+		t.Fatalf("the account of the sdk has %d fields, expected %d, check newAgentAccount with the new sdk", accountType.NumField(), len(want))
+	}
+	for i, field := range want {
+		if got := accountType.Field(i); got.Name != field.name || got.Type != field.typ {
+			t.Fatalf("got the field %s %s of the account of the sdk, expected %s %s, check newAgentAccount with the new sdk",
+				got.Name, got.Type, field.name, field.typ)
+		}
+	}
+}
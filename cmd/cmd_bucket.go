@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
 
 	"cosmossdk.io/math"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -34,14 +33,16 @@ Examples:
 $ gnfd-cmd bucket create --visibility=public-read  --tags='[{"key":"key1","value":"value1"},{"key":"key2","value":"value2"}]' gnfd://gnfd-bucket`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:  primarySPFlag,
-				Value: "",
-				Usage: "indicate the primarySP address, using the string type",
+				Name:    primarySPFlag,
+				Value:   "",
+				Usage:   "indicate the primarySP address, using the string type",
+				EnvVars: []string{primarySPEnv},
 			},
 			&cli.StringFlag{
-				Name:  paymentFlag,
-				Value: "",
-				Usage: "indicate the PaymentAddress info, using the string type",
+				Name:    paymentFlag,
+				Value:   "",
+				Usage:   "indicate the PaymentAddress info, using the string type",
+				EnvVars: []string{paymentAddressEnv},
 			},
 			&cli.Uint64Flag{
 				Name:  chargeQuotaFlag,
@@ -54,7 +55,8 @@ $ gnfd-cmd bucket create --visibility=public-read  --tags='[{"key":"key1","value
 					Enum:    []string{publicReadType, privateType, inheritType},
 					Default: privateType,
 				},
-				Usage: "set visibility of the bucket",
+				Usage:   "set visibility of the bucket",
+				EnvVars: []string{bucketVisibilityEnv},
 			},
 			&cli.StringFlag{
 				Name:  tagFlag,
@@ -217,6 +219,9 @@ func setTagForBucket(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "set_tag", Resource: grn.String(), TxnHash: txnHash})
+	}
 	return nil
 }
 
@@ -246,7 +251,10 @@ func createBucket(ctx *cli.Context) error {
 	}
 
 	opts := sdktypes.CreateBucketOptions{}
-	paymentAddrStr := ctx.String(paymentFlag)
+	paymentAddrStr, err := getAddressFlag(ctx, paymentFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	if paymentAddrStr != "" {
 		opts.PaymentAddress = paymentAddrStr
 	}
@@ -280,8 +288,11 @@ func createBucket(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Printf("make_bucket: %s \n", bucketName)
-	fmt.Println("transaction hash: ", txnHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "make_bucket", Resource: bucketName, TxnHash: txnHash})
+	}
+	fmt.Fprintf(infoWriter, "make_bucket: %s \n", bucketName)
+	fmt.Fprintln(infoWriter, "transaction hash: ", txnHash)
 	return nil
 }
 
@@ -307,7 +318,10 @@ func updateBucket(ctx *cli.Context) error {
 	}
 
 	opts := sdktypes.UpdateBucketOptions{}
-	paymentAddrStr := ctx.String(paymentFlag)
+	paymentAddrStr, err := getAddressFlag(ctx, paymentFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	if paymentAddrStr != "" {
 		opts.PaymentAddress = paymentAddrStr
 	}
@@ -330,8 +344,7 @@ func updateBucket(ctx *cli.Context) error {
 
 	txnHash, err := client.UpdateBucketInfo(c, bucketName, opts)
 	if err != nil {
-		fmt.Println("update bucket error:", err.Error())
-		return nil
+		return toCmdErr(err)
 	}
 
 	err = waitTxnStatus(client, c, txnHash, "UpdateBucket")
@@ -339,17 +352,33 @@ func updateBucket(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "update_bucket", Resource: bucketName, TxnHash: txnHash})
+	}
+
 	bucketInfo, err := client.HeadBucket(c, bucketName)
 	if err != nil {
 		// head fail, no need to print the error
 		return nil
 	}
 
-	fmt.Printf("latest bucket meta on chain:\nvisibility:%s\nread quota:%d\npayment address:%s \n", bucketInfo.GetVisibility().String(),
+	fmt.Fprintf(infoWriter, "latest bucket meta on chain:\nvisibility:%s\nread quota:%d\npayment address:%s \n", bucketInfo.GetVisibility().String(),
 		bucketInfo.GetChargedReadQuota(), bucketInfo.GetPaymentAddress())
 	return nil
 }
 
+// bucketListRecord is the record of the buckets listed by "bucket ls"
+type bucketListRecord struct {
+	BucketName     string `json:"bucket_name"`
+	BucketID       string `json:"bucket_id"`
+	Owner          string `json:"owner"`
+	Visibility     string `json:"visibility"`
+	Status         string `json:"status"`
+	PaymentAddress string `json:"payment_address"`
+	ChargedQuota   uint64 `json:"charged_read_quota"`
+	CreateTime     string `json:"create_time"`
+}
+
 // listBuckets list the buckets of the specific owner
 func listBuckets(ctx *cli.Context) error {
 	client, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
@@ -362,8 +391,7 @@ func listBuckets(ctx *cli.Context) error {
 
 	spInfo, err := client.ListStorageProviders(c, true)
 	if err != nil {
-		fmt.Println("fail to get SP info to list bucket:", err.Error())
-		return nil
+		return toCmdErr(fmt.Errorf("fail to get SP info to list bucket: %v", err))
 	}
 
 	bucketListRes, err := client.ListBuckets(c, sdktypes.ListBucketsOptions{ShowRemovedBucket: false,
@@ -379,11 +407,27 @@ func listBuckets(ctx *cli.Context) error {
 
 	for _, bucket := range bucketListRes.Buckets {
 		info := bucket.BucketInfo
+		if isStructuredOutput() {
+			if bucket.Removed {
+				continue
+			}
+			if err = printRecord(bucketListRecord{
+				BucketName:     info.BucketName,
+				BucketID:       info.Id.String(),
+				Owner:          info.Owner,
+				Visibility:     info.Visibility.String(),
+				Status:         info.BucketStatus.String(),
+				PaymentAddress: info.PaymentAddress,
+				ChargedQuota:   info.ChargedReadQuota,
+				CreateTime:     formatRecordTime(info.CreateAt),
+			}); err != nil {
+				return toCmdErr(err)
+			}
+			continue
+		}
 
-		location, _ := time.LoadLocation("Asia/Shanghai")
-		t := time.Unix(info.CreateAt, 0).In(location)
 		if !bucket.Removed {
-			fmt.Printf("%s  %s\n", t.Format(iso8601DateFormat), info.BucketName)
+			fmt.Fprintf(infoWriter, "%s  %s\n", formatTime(info.CreateAt), info.BucketName)
 		}
 	}
 	return nil
@@ -409,6 +453,9 @@ func mirrorBucket(ctx *cli.Context) error {
 	if err != nil {
 		return toCmdErr(err)
 	}
-	fmt.Printf("mirror bucket succ, txHash: %s\n", txResp.TxHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "mirror_bucket", Resource: bucketName, ResourceID: id.String(), TxnHash: txResp.TxHash})
+	}
+	fmt.Fprintf(infoWriter, "mirror bucket succ, txHash: %s\n", txResp.TxHash)
 	return nil
 }
@@ -0,0 +1,765 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/greenfield-go-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/BurntSushi/toml"
+	"github.com/urfave/cli/v2"
+)
+
+// networkProfile is a named network in the config file, the account is the address of the keystore used by
+// default when the profile is in use
+type networkProfile struct {
+	RpcAddr string `toml:"rpcAddr" json:"rpc_addr"`
+	ChainId string `toml:"chainId" json:"chain_id"`
+	Host    string `toml:"host,omitempty" json:"host,omitempty"`
+	Account string `toml:"account,omitempty" json:"account,omitempty"`
+}
+
+// builtinProfiles are the presets of the public networks, the profiles of the config file with the same name
+// take precedence over them
+var builtinProfiles = map[string]networkProfile{
+	"mainnet": {RpcAddr: "https://greenfield-chain.bnbchain.org:443", ChainId: "greenfield_1017-1"},
+	"testnet": {RpcAddr: "https://gnfd-testnet-fullnode-tendermint-us.bnbchain.org:443", ChainId: "greenfield_5600-1"},
+	"local":   {RpcAddr: "http://localhost:26750", ChainId: "greenfield_9000-121-1"},
+}
+
+// profileRecord is the record of the profile printed by "config use-profile"
+type profileRecord struct {
+	Name string `json:"name"`
+	networkProfile
+}
+
+// cmdUseProfile set the profile used by the commands
+func cmdUseProfile() *cli.Command {
+	return &cli.Command{
+		Name:      "use-profile",
+		Action:    useProfile,
+		Usage:     "set the network profile in use",
+		ArgsUsage: "PROFILE-NAME",
+		Description: `
+Set the profile used by the commands when the global "--profile" flag is not set.
+The profile can be defined in the config file or be one of the built-in presets: mainnet, testnet and local.
+
+A profile of the config file looks like this:
+
+[profiles.devnet]
+rpcAddr = "http://localhost:26750"
+chainId = "greenfield_9000-121-1"
+account = "0x5a64aCD8DC6Ce41d824638419319409246A9b41A"
+
+Examples:
+$ gnfd-cmd config use-profile testnet
+$ gnfd-cmd --profile mainnet bucket ls`,
+	}
+}
+
+// cmdInitConfig create the config file
+func cmdInitConfig() *cli.Command {
+	return &cli.Command{
+		Name:   "init",
+		Action: initConfig,
+		Usage:  "create the config file",
+		Description: `
+Create the config file with the network of a built-in preset: mainnet, testnet or local.
+The global "--rpcAddr", "--chainId" and "--host" flags can be set to create the config file of another network.
+The existing config file is not overwritten unless --force is set.
+
+Examples:
+$ gnfd-cmd config init --network testnet
+$ gnfd-cmd --rpcAddr http://localhost:26750 --chainId greenfield_9000-121-1 config init --force`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  networkFlag,
+				Value: "mainnet",
+				Usage: "the built-in preset of the network: mainnet, testnet or local",
+			},
+			&cli.BoolFlag{
+				Name:  forceFlag,
+				Value: false,
+				Usage: "overwrite the existing config file",
+			},
+		},
+	}
+}
+
+// cmdShowConfig print the config file and the network in use
+func cmdShowConfig() *cli.Command {
+	return &cli.Command{
+		Name:   "show",
+		Action: showConfig,
+		Usage:  "show the config file and the network in use",
+		Description: `
+Show the path and the settings of the config file, and the network resolved from the flags, the profile in use and the config file.
+
+Examples:
+$ gnfd-cmd config show
+$ gnfd-cmd --profile testnet config show`,
+	}
+}
+
+// cmdGetConfig print a setting of the config file
+func cmdGetConfig() *cli.Command {
+	return &cli.Command{
+		Name:      "get",
+		Action:    getConfigValue,
+		Usage:     "get a setting of the config file",
+		ArgsUsage: "KEY",
+		Description: `
+Print a setting of the config file. The settings of the profiles are named like "profiles.NAME.KEY".
+
+Examples:
+$ gnfd-cmd config get rpcAddr
+$ gnfd-cmd config get profiles.devnet.chainId`,
+	}
+}
+
+// cmdSetConfig update a setting of the config file
+func cmdSetConfig() *cli.Command {
+	return &cli.Command{
+		Name:      "set",
+		Action:    setConfigValue,
+		Usage:     "update a setting of the config file",
+		ArgsUsage: "KEY VALUE",
+		Description: `
+Update a setting of the config file, the setting is removed if the value is empty.
+The settings are rpcAddr, chainId, host, profile, limit-rate, timezone and timeFormat, and the settings of the
+profiles are named like "profiles.NAME.KEY" where KEY is rpcAddr, chainId, host or account.
+The comments of the config file are not kept after updating.
+
+Examples:
+$ gnfd-cmd config set rpcAddr https://greenfield-chain.bnbchain.org:443
+$ gnfd-cmd config set profiles.devnet.rpcAddr http://localhost:26750
+$ gnfd-cmd config set timezone ""`,
+	}
+}
+
+// cmdValidateConfig check the network of the config
+func cmdValidateConfig() *cli.Command {
+	return &cli.Command{
+		Name:   "validate",
+		Action: validateConfig,
+		Usage:  "check the rpc address, the chain id and the storage providers of the config",
+		Description: `
+Check that the rpc address of the network in use is reachable, that the chainId matches the chain id reported by the node,
+and that the storage providers in service respond. The failed checks are printed with the hints to fix them.
+
+Examples:
+$ gnfd-cmd config validate
+$ gnfd-cmd --profile testnet config validate`,
+	}
+}
+
+// getProfile return the profile of the config file or the built-in preset with the name
+func (c *cmdConfig) getProfile(name string) (*networkProfile, error) {
+	if profile, ok := c.Profiles[name]; ok && profile != nil {
+		p := *profile
+		return &p, nil
+	}
+	if profile, ok := builtinProfiles[name]; ok {
+		return &profile, nil
+	}
+	return nil, fmt.Errorf("profile %s not found, the available profiles are %v", name, c.profileNames())
+}
+
+// profileNames return the sorted names of the profiles of the config file and the built-in presets
+func (c *cmdConfig) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles)+len(builtinProfiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	for name := range builtinProfiles {
+		if _, ok := c.Profiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getProfileName return the name of the profile in use, the profile flag takes precedence over the config file
+func getProfileName(ctx *cli.Context, config *cmdConfig) string {
+	if name := ctx.String(profileFlag); name != "" {
+		return name
+	}
+	return config.Profile
+}
+
+// getProfileAccount return the account of the profile in use, empty string is returned if no profile is used
+// or the profile has no account. The config file is not generated if it does not exist.
+func getProfileAccount(ctx *cli.Context) (string, error) {
+	configPath, err := getConfigPath(ctx)
+	if err != nil {
+		return "", err
+	}
+	config := &cmdConfig{}
+	if _, err = os.Stat(configPath); err == nil {
+		if config, err = parseConfigFile(configPath); err != nil {
+			return "", fmt.Errorf("failed to read config file: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to check config file: %v", err)
+	}
+
+	profileName := getProfileName(ctx, config)
+	if profileName == "" {
+		return "", nil
+	}
+	profile, err := config.getProfile(profileName)
+	if err != nil {
+		return "", err
+	}
+	return profile.Account, nil
+}
+
+func useProfile(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return toCmdErr(fmt.Errorf("args number should be 1"))
+	}
+	profileName := ctx.Args().Get(0)
+
+	configPath, err := getConfigPath(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if ctx.String(configFlag) == "" {
+		// check the config file exists, it is created by "gnfd-cmd config init"
+		if _, err = loadConfig(ctx); err != nil {
+			return toCmdErr(err)
+		}
+	}
+
+	config, err := parseConfigFile(configPath)
+	if err != nil {
+		return toCmdErr(fmt.Errorf("failed to read config file: %v", err))
+	}
+	profile, err := config.getProfile(profileName)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	content, err := readConfigContent(configPath)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	content["profile"] = profileName
+	if err = writeConfigFile(configPath, content); err != nil {
+		return toCmdErr(err)
+	}
+
+	if isStructuredOutput() {
+		return printRecord(profileRecord{Name: profileName, networkProfile: *profile})
+	}
+	fmt.Fprintf(infoWriter, "the profile in use is %s, rpc address: %s, chain id: %s \n", profileName, profile.RpcAddr, profile.ChainId)
+	return nil
+}
+
+// writeConfigFile encode the config to TOML and write it to the config file, the comments of the config file are not kept
+func writeConfigFile(configPath string, content interface{}) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(content); err != nil {
+		return fmt.Errorf("failed to encode config file: %v", err)
+	}
+	return writeConfigContent(configPath, buf.Bytes())
+}
+
+// writeConfigContent write the content to a temp file and rename it to the config file, so that the config file
+// is never half written
+func writeConfigContent(configPath string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		return errors.New("failed to create config file directory: " + filepath.Dir(configPath))
+	}
+	tempPath := configPath + ".tmp"
+	if err := os.WriteFile(tempPath, content, 0644); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+	return os.Rename(tempPath, configPath)
+}
+
+// readConfigContent decode the config file into a map, so that the settings unknown to cmdConfig are kept when rewriting it
+func readConfigContent(configPath string) (map[string]interface{}, error) {
+	content := make(map[string]interface{})
+	if _, err := toml.DecodeFile(configPath, &content); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("the config file %s does not exist, run \"gnfd-cmd config init\" to create it", configPath)
+		}
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+	return content, nil
+}
+
+// the validators of the settings of the config file, nil means any value is valid
+var (
+	configKeyValidators = map[string]func(config *cmdConfig, value string) error{
+		rpcAddrConfigField: func(_ *cmdConfig, value string) error { return validateRpcAddr(value) },
+		chainIdConfigField: nil,
+		hostConfigField:    nil,
+		profileFlag: func(config *cmdConfig, value string) error {
+			_, err := config.getProfile(value)
+			return err
+		},
+		limitRateFlag: func(_ *cmdConfig, value string) error {
+			_, err := parseRate(value)
+			return err
+		},
+		timezoneFlag: func(_ *cmdConfig, value string) error {
+			_, err := parseTimezone(value)
+			return err
+		},
+		timeFormatFlag: func(_ *cmdConfig, value string) error {
+			_, err := parseTimeLayout(value)
+			return err
+		},
+	}
+	profileKeyValidators = map[string]func(value string) error{
+		rpcAddrConfigField: validateRpcAddr,
+		chainIdConfigField: nil,
+		hostConfigField:    nil,
+		// the account can be an address or the name of an account
+		"account": func(value string) error {
+			if _, err := sdk.AccAddressFromHexUnsafe(value); err == nil {
+				return nil
+			}
+			return validateAccountName(value)
+		},
+	}
+)
+
+func validateRpcAddr(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid rpc address %s, it should be like https://greenfield-chain.bnbchain.org:443", value)
+	}
+	return nil
+}
+
+// splitConfigKey split the key like "profiles.devnet.rpcAddr" and check that it is a known setting
+func splitConfigKey(key string) ([]string, error) {
+	parts := strings.Split(key, ".")
+	if len(parts) == 1 {
+		if _, ok := configKeyValidators[key]; ok {
+			return parts, nil
+		}
+	} else if len(parts) == 3 && parts[0] == "profiles" && parts[1] != "" {
+		if _, ok := profileKeyValidators[parts[2]]; ok {
+			return parts, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid format of the config key %s, see \"gnfd-cmd config set -h\" for the settings", key)
+}
+
+func initConfig(ctx *cli.Context) error {
+	configPath, err := getConfigPath(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	if _, err = os.Stat(configPath); err == nil && !ctx.Bool(forceFlag) {
+		return toCmdErr(fmt.Errorf("the config file %s already exists, set --force to overwrite it", configPath))
+	}
+
+	networkName := ctx.String(networkFlag)
+	network, ok := builtinProfiles[networkName]
+	if !ok {
+		return toCmdErr(fmt.Errorf("invalid network %s, the allowed values are mainnet, testnet and local", networkName))
+	}
+	if rpcAddr := ctx.String(rpcAddrConfigField); rpcAddr != "" {
+		network.RpcAddr = rpcAddr
+	}
+	if chainId := ctx.String(chainIdConfigField); chainId != "" {
+		network.ChainId = chainId
+	}
+	network.Host = ctx.String(hostConfigField)
+	if err = validateRpcAddr(network.RpcAddr); err != nil {
+		return toCmdErr(err)
+	}
+
+	content := fmt.Sprintf("# the network of the commands, run \"gnfd-cmd config validate\" to check it\n"+
+		"rpcAddr = %q\nchainId = %q\n", network.RpcAddr, network.ChainId)
+	if network.Host != "" {
+		content += fmt.Sprintf("host = %q\n", network.Host)
+	}
+	if err = writeConfigContent(configPath, []byte(content)); err != nil {
+		return toCmdErr(err)
+	}
+
+	if isStructuredOutput() {
+		return printRecord(configRecord{ConfigPath: configPath, RpcAddr: network.RpcAddr, ChainId: network.ChainId, Host: network.Host})
+	}
+	fmt.Fprintf(infoWriter, "the config file is created at %s, rpc address: %s, chain id: %s \n", configPath, network.RpcAddr, network.ChainId)
+	return nil
+}
+
+// configRecord is the record of the config printed by "config show" and "config init"
+type configRecord struct {
+	ConfigPath string                 `json:"config_path"`
+	Profile    string                 `json:"profile,omitempty"`
+	RpcAddr    string                 `json:"rpc_addr"`
+	ChainId    string                 `json:"chain_id"`
+	Host       string                 `json:"host,omitempty"`
+	Account    string                 `json:"account,omitempty"`
+	Settings   map[string]interface{} `json:"settings,omitempty"`
+}
+
+func showConfig(ctx *cli.Context) error {
+	configPath, err := getConfigPath(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	content, err := readConfigContent(configPath)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	config, err := parseConfigFile(configPath)
+	if err != nil {
+		return toCmdErr(fmt.Errorf("failed to read config file: %v", err))
+	}
+	rpcAddr, chainId, host, err := getConfig(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	account, err := getProfileAccount(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	record := configRecord{
+		ConfigPath: configPath,
+		Profile:    getProfileName(ctx, config),
+		RpcAddr:    rpcAddr,
+		ChainId:    chainId,
+		Host:       host,
+		Account:    account,
+		Settings:   content,
+	}
+	if isStructuredOutput() {
+		return printRecord(record)
+	}
+
+	fmt.Fprintln(infoWriter, "config file:", record.ConfigPath)
+	if record.Profile != "" {
+		fmt.Fprintln(infoWriter, "profile in use:", record.Profile)
+	}
+	fmt.Fprintln(infoWriter, "rpc address:", record.RpcAddr)
+	fmt.Fprintln(infoWriter, "chain id:", record.ChainId)
+	if record.Host != "" {
+		fmt.Fprintln(infoWriter, "host:", record.Host)
+	}
+	if record.Account != "" {
+		fmt.Fprintln(infoWriter, "account of the profile:", record.Account)
+	}
+	fmt.Fprintln(infoWriter, "settings of the config file:")
+	var buf bytes.Buffer
+	if err = toml.NewEncoder(&buf).Encode(content); err != nil {
+		return toCmdErr(err)
+	}
+	fmt.Fprint(infoWriter, buf.String())
+	return nil
+}
+
+func getConfigValue(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return toCmdErr(fmt.Errorf("args number should be 1"))
+	}
+	key := ctx.Args().Get(0)
+	parts, err := splitConfigKey(key)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	configPath, err := getConfigPath(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	content, err := readConfigContent(configPath)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	var value interface{} = content
+	for _, part := range parts {
+		settings, ok := value.(map[string]interface{})
+		if !ok {
+			value = nil
+			break
+		}
+		value = settings[part]
+	}
+	if value == nil {
+		return toCmdErr(fmt.Errorf("the config key %s is not set in %s", key, configPath))
+	}
+
+	if isStructuredOutput() {
+		return printRecord(map[string]interface{}{"key": key, "value": value})
+	}
+	fmt.Fprintln(infoWriter, value)
+	return nil
+}
+
+func setConfigValue(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return toCmdErr(fmt.Errorf("args number should be 2"))
+	}
+	key, value := ctx.Args().Get(0), ctx.Args().Get(1)
+	parts, err := splitConfigKey(key)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	configPath, err := getConfigPath(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	content, err := readConfigContent(configPath)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	config, err := parseConfigFile(configPath)
+	if err != nil {
+		return toCmdErr(fmt.Errorf("failed to read config file: %v", err))
+	}
+
+	if value != "" {
+		var validateErr error
+		if len(parts) == 1 {
+			if validator := configKeyValidators[key]; validator != nil {
+				validateErr = validator(config, value)
+			}
+		} else if validator := profileKeyValidators[parts[2]]; validator != nil {
+			validateErr = validator(value)
+		}
+		if validateErr != nil {
+			return newCmdError(exitCodeUsage, fmt.Errorf("invalid value of %s: %v", key, validateErr))
+		}
+	}
+
+	settings := content
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := settings[part].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			settings[part] = child
+		}
+		settings = child
+	}
+	if value == "" {
+		delete(settings, parts[len(parts)-1])
+	} else {
+		settings[parts[len(parts)-1]] = value
+	}
+	if err = writeConfigFile(configPath, content); err != nil {
+		return toCmdErr(err)
+	}
+
+	if isStructuredOutput() {
+		return printRecord(map[string]interface{}{"key": key, "value": value})
+	}
+	if value == "" {
+		fmt.Fprintf(infoWriter, "%s is removed from %s \n", key, configPath)
+	} else {
+		fmt.Fprintf(infoWriter, "%s is set to %s in %s \n", key, value, configPath)
+	}
+	return nil
+}
+
+const configCheckTimeout = 10 * time.Second
+
+// configCheckRecord is the result of a check of "config validate"
+type configCheckRecord struct {
+	Check   string `json:"check"`
+	Target  string `json:"target"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+func printConfigCheck(check configCheckRecord) error {
+	if isStructuredOutput() {
+		return printRecord(check)
+	}
+	status := "[ok]"
+	if !check.OK {
+		status = "[fail]"
+	}
+	fmt.Fprintf(infoWriter, "%-6s %-17s %s\n", status, check.Check, check.Target)
+	if check.Message != "" {
+		fmt.Fprintf(infoWriter, "       %s\n", check.Message)
+	}
+	if check.Hint != "" {
+		fmt.Fprintf(infoWriter, "       hint: %s\n", check.Hint)
+	}
+	return nil
+}
+
+// nodeStatus is the part of the response of the /status endpoint of the tendermint rpc used by the checks
+type nodeStatus struct {
+	Result struct {
+		NodeInfo struct {
+			Network string `json:"network"`
+		} `json:"node_info"`
+		SyncInfo struct {
+			LatestBlockHeight string `json:"latest_block_height"`
+			CatchingUp        bool   `json:"catching_up"`
+		} `json:"sync_info"`
+	} `json:"result"`
+}
+
+// getNodeStatus query the status of the node by the tendermint rpc
+func getNodeStatus(c context.Context, rpcAddr string) (*nodeStatus, error) {
+	req, err := http.NewRequestWithContext(c, http.MethodGet, strings.TrimSuffix(rpcAddr, "/")+"/status", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the node responded with http status %s", resp.Status)
+	}
+	status := &nodeStatus{}
+	if err = json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, fmt.Errorf("the response of the node is not a tendermint status: %v", err)
+	}
+	return status, nil
+}
+
+// checkEndpoint check that the endpoint responds to http requests, any http status means the endpoint is reachable
+func checkEndpoint(c context.Context, endpoint string) error {
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "https://" + endpoint
+	}
+	req, err := http.NewRequestWithContext(c, http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/status", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func validateConfig(ctx *cli.Context) error {
+	var checks, failed int
+	report := func(check configCheckRecord) error {
+		checks++
+		if !check.OK {
+			failed++
+		}
+		return printConfigCheck(check)
+	}
+
+	rpcAddr, chainId, host, err := getConfig(ctx)
+	if err != nil {
+		report(configCheckRecord{Check: "config", Message: err.Error(),
+			Hint: "run \"gnfd-cmd config init\" to create the config file, or set rpcAddr and chainId by \"gnfd-cmd config set\""})
+		return toCmdErr(fmt.Errorf("the config is invalid: %v", err))
+	}
+
+	c, cancel := context.WithTimeout(globalContext, configCheckTimeout)
+	status, err := getNodeStatus(c, rpcAddr)
+	cancel()
+	if err != nil {
+		report(configCheckRecord{Check: "rpc", Target: rpcAddr, Message: err.Error(),
+			Hint: "check the rpcAddr of the config file or the profile, it should be the tendermint rpc address with the port " +
+				"like https://greenfield-chain.bnbchain.org:443"})
+		return toCmdErr(fmt.Errorf("the rpc address %s is not reachable: %v", rpcAddr, err))
+	}
+	rpcCheck := configCheckRecord{Check: "rpc", Target: rpcAddr, OK: true,
+		Message: "latest block height " + status.Result.SyncInfo.LatestBlockHeight}
+	if status.Result.SyncInfo.CatchingUp {
+		rpcCheck.Message += ", the node is catching up so the latest data may be missing"
+	}
+	if err = report(rpcCheck); err != nil {
+		return toCmdErr(err)
+	}
+
+	network := status.Result.NodeInfo.Network
+	chainCheck := configCheckRecord{Check: "chain id", Target: chainId, OK: network == chainId}
+	if !chainCheck.OK {
+		chainCheck.Message = fmt.Sprintf("the node reports chain id %s", network)
+		chainCheck.Hint = fmt.Sprintf("set chainId to %s, or set rpcAddr to a node of the chain %s", network, chainId)
+	}
+	if err = report(chainCheck); err != nil {
+		return toCmdErr(err)
+	}
+
+	if chainCheck.OK {
+		if err = checkStorageProviders(rpcAddr, chainId, host, report); err != nil {
+			return toCmdErr(err)
+		}
+	}
+
+	if failed > 0 {
+		return toCmdErr(fmt.Errorf("%d of %d checks of the config failed", failed, checks))
+	}
+	if !isStructuredOutput() {
+		fmt.Fprintln(infoWriter, "the config is valid")
+	}
+	return nil
+}
+
+// checkStorageProviders check that the storage providers in service respond, the endpoints are checked at the same time
+func checkStorageProviders(rpcAddr, chainId, host string, report func(check configCheckRecord) error) error {
+	spCheck := configCheckRecord{Check: "storage providers", Target: rpcAddr}
+	gnfdClient, err := client.New(chainId, rpcAddr, client.Option{Host: host})
+	if err != nil {
+		spCheck.Message = err.Error()
+		spCheck.Hint = "the storage providers can not be queried from the node, check the rpcAddr of the config"
+		return report(spCheck)
+	}
+	c, cancel := context.WithTimeout(globalContext, configCheckTimeout)
+	spList, err := gnfdClient.ListStorageProviders(c, true)
+	cancel()
+	if err != nil {
+		spCheck.Message = err.Error()
+		spCheck.Hint = "the storage providers can not be queried from the node, check the rpcAddr of the config"
+		return report(spCheck)
+	}
+	if len(spList) == 0 {
+		spCheck.Message = "no storage provider is in service"
+		spCheck.Hint = "the objects can not be stored on the network, check that the rpcAddr belongs to the expected network"
+		return report(spCheck)
+	}
+
+	results := make([]configCheckRecord, len(spList))
+	var wg sync.WaitGroup
+	for i, sp := range spList {
+		wg.Add(1)
+		go func(i int, moniker, endpoint string) {
+			defer wg.Done()
+			results[i] = configCheckRecord{Check: "storage provider", Target: endpoint, OK: true, Message: moniker}
+			checkCtx, cancelCheck := context.WithTimeout(globalContext, configCheckTimeout)
+			defer cancelCheck()
+			if checkErr := checkEndpoint(checkCtx, endpoint); checkErr != nil {
+				results[i].OK = false
+				results[i].Message = fmt.Sprintf("%s: %v", moniker, checkErr)
+				results[i].Hint = "the storage provider may be down or blocked by the network, " +
+					"the buckets stored on it can not be accessed from this machine"
+			}
+		}(i, sp.Description.GetMoniker(), sp.Endpoint)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if err = report(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
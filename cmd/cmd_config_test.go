@@ -0,0 +1,307 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+const testConfigContent = `rpcAddr = "http://file:26750"
+chainId = "greenfield_1-1"
+
+[profiles.staging]
+rpcAddr = "http://profile:26750"
+chainId = "greenfield_2-1"
+host = "profile-host"
+`
+
+// runGetConfig run an app with the network flags of the command and return the result of getConfig
+func runGetConfig(t *testing.T, args []string) (string, string, string, error) {
+	var (
+		rpcAddr, chainId, host string
+		configErr              error
+	)
+	app := &cli.App{
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: hostConfigField, EnvVars: []string{hostEnv}},
+			&cli.StringFlag{Name: rpcAddrConfigField, EnvVars: []string{rpcAddrEnv}},
+			&cli.StringFlag{Name: chainIdConfigField, EnvVars: []string{chainIdEnv}},
+			&cli.StringFlag{Name: profileFlag, EnvVars: []string{profileEnv}},
+			&cli.StringFlag{Name: configFlag, EnvVars: []string{configEnv}},
+			&cli.StringFlag{Name: homeFlag, EnvVars: []string{homeEnv}},
+		},
+		Action: func(ctx *cli.Context) error {
+			rpcAddr, chainId, host, configErr = getConfig(ctx)
+			return nil
+		},
+	}
+	if err := app.Run(append([]string{"gnfd-cmd"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	return rpcAddr, chainId, host, configErr
+}
+
+// writeTestConfigs write a config file without a profile in use and a config file using the staging profile
+func writeTestConfigs(t *testing.T) (string, string, string) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(testConfigContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+	profileConfigPath := filepath.Join(dir, "profile.toml")
+	if err := os.WriteFile(profileConfigPath, []byte("profile = \"staging\"\n"+testConfigContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir, configPath, profileConfigPath
+}
+
+// clearConfigEnv unset the environment variables of the network flags until the test ends
+func clearConfigEnv(t *testing.T) {
+	for _, env := range []string{hostEnv, rpcAddrEnv, chainIdEnv, profileEnv, configEnv, homeEnv} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestGetConfigProfile(t *testing.T) {
+	dir, configPath, profileConfigPath := writeTestConfigs(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		rpcAddr string
+		chainId string
+		host    string
+		wantErr bool
+	}{
+		{name: "file", args: []string{"--config", configPath}, rpcAddr: "http://file:26750", chainId: "greenfield_1-1"},
+		{
+			name:    "profile of the file",
+			args:    []string{"--config", profileConfigPath},
+			rpcAddr: "http://profile:26750", chainId: "greenfield_2-1", host: "profile-host",
+		},
+		{
+			name:    "profile flag",
+			args:    []string{"--config", configPath, "--profile", "staging"},
+			rpcAddr: "http://profile:26750", chainId: "greenfield_2-1", host: "profile-host",
+		},
+		{
+			name:    "built-in profile",
+			args:    []string{"--config", configPath, "--profile", "local"},
+			rpcAddr: "http://localhost:26750", chainId: "greenfield_9000-121-1",
+		},
+		{
+			name:    "flag over profile",
+			args:    []string{"--config", profileConfigPath, "--rpcAddr", "http://flag:26750"},
+			rpcAddr: "http://flag:26750", chainId: "greenfield_2-1", host: "profile-host",
+		},
+		{
+			name:    "flags without config file",
+			args:    []string{"--home", dir, "--rpcAddr", "http://flag:26750", "--chainId", "greenfield_4-1"},
+			rpcAddr: "http://flag:26750", chainId: "greenfield_4-1",
+		},
+		{
+			name:    "preset with no config file",
+			args:    []string{"--home", dir, "--profile", "local"},
+			rpcAddr: "http://localhost:26750", chainId: "greenfield_9000-121-1",
+		},
+		{name: "unknown profile", args: []string{"--config", configPath, "--profile", "unknown"}, wantErr: true},
+		{name: "unknown profile with no config file", args: []string{"--home", dir, "--profile", "unknown"}, wantErr: true},
+		{name: "missing config file", args: []string{"--home", dir}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv(t)
+			rpcAddr, chainId, host, err := runGetConfig(t, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s %s", rpcAddr, chainId)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rpcAddr != tt.rpcAddr || chainId != tt.chainId || host != tt.host {
+				t.Errorf("got (%s, %s, %s), expected (%s, %s, %s)", rpcAddr, chainId, host, tt.rpcAddr, tt.chainId, tt.host)
+			}
+		})
+	}
+}
+
+func TestGetConfigEnv(t *testing.T) {
+	_, configPath, profileConfigPath := writeTestConfigs(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		rpcAddr string
+		chainId string
+		host    string
+	}{
+		{
+			name:    "profile env",
+			args:    []string{"--config", configPath},
+			env:     map[string]string{profileEnv: "staging"},
+			rpcAddr: "http://profile:26750", chainId: "greenfield_2-1", host: "profile-host",
+		},
+		{
+			name:    "profile flag over env",
+			args:    []string{"--config", configPath, "--profile", "local"},
+			env:     map[string]string{profileEnv: "staging"},
+			rpcAddr: "http://localhost:26750", chainId: "greenfield_9000-121-1",
+		},
+		{
+			name:    "env over profile",
+			args:    []string{"--config", profileConfigPath},
+			env:     map[string]string{rpcAddrEnv: "http://env:26750"},
+			rpcAddr: "http://env:26750", chainId: "greenfield_2-1", host: "profile-host",
+		},
+		{
+			name:    "env over file",
+			args:    []string{"--config", configPath},
+			env:     map[string]string{chainIdEnv: "greenfield_3-1"},
+			rpcAddr: "http://file:26750", chainId: "greenfield_3-1",
+		},
+		{
+			name:    "flag over env",
+			args:    []string{"--config", profileConfigPath, "--rpcAddr", "http://flag:26750"},
+			env:     map[string]string{rpcAddrEnv: "http://env:26750"},
+			rpcAddr: "http://flag:26750", chainId: "greenfield_2-1", host: "profile-host",
+		},
+		{
+			name:    "config file env",
+			env:     map[string]string{configEnv: profileConfigPath},
+			rpcAddr: "http://profile:26750", chainId: "greenfield_2-1", host: "profile-host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv(t)
+			for env, value := range tt.env {
+				t.Setenv(env, value)
+			}
+
+			rpcAddr, chainId, host, err := runGetConfig(t, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rpcAddr != tt.rpcAddr || chainId != tt.chainId || host != tt.host {
+				t.Errorf("got (%s, %s, %s), expected (%s, %s, %s)", rpcAddr, chainId, host, tt.rpcAddr, tt.chainId, tt.host)
+			}
+		})
+	}
+}
+
+func TestSplitConfigKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    int
+		wantErr bool
+	}{
+		{key: "rpcAddr", want: 1},
+		{key: "limit-rate", want: 1},
+		{key: "profiles.devnet.rpcAddr", want: 3},
+		{key: "profiles.devnet.account", want: 3},
+		{key: "account", wantErr: true},
+		{key: "profiles..rpcAddr", wantErr: true},
+		{key: "profiles.devnet.limit-rate", wantErr: true},
+		{key: "networks.devnet.rpcAddr", wantErr: true},
+		{key: "profiles.devnet", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			parts, err := splitConfigKey(tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", parts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(parts) != tt.want {
+				t.Errorf("got %v, expected %d parts", parts, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetConfigValue(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		get     func(config *cmdConfig) string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "top-level setting", key: "chainId", value: "greenfield_5-1",
+			get: func(config *cmdConfig) string { return config.ChainId }, want: "greenfield_5-1",
+		},
+		{
+			name: "new profile", key: "profiles.devnet.rpcAddr", value: "http://devnet:26750",
+			get: func(config *cmdConfig) string { return config.Profiles["devnet"].RpcAddr }, want: "http://devnet:26750",
+		},
+		{
+			name: "setting of a profile", key: "profiles.staging.host", value: "staging-host",
+			get: func(config *cmdConfig) string { return config.Profiles["staging"].Host }, want: "staging-host",
+		},
+		{
+			name: "remove a setting", key: "profiles.staging.host",
+			get: func(config *cmdConfig) string { return config.Profiles["staging"].Host },
+		},
+		{
+			name: "profile in use", key: "profile", value: "staging",
+			get: func(config *cmdConfig) string { return config.Profile }, want: "staging",
+		},
+		{name: "unknown profile", key: "profile", value: "unknown", wantErr: true},
+		{name: "invalid rpc address", key: "profiles.devnet.rpcAddr", value: "localhost:26750", wantErr: true},
+		{name: "invalid rate", key: "limit-rate", value: "fast", wantErr: true},
+		{name: "unknown key", key: "endpoint", value: "http://localhost:26750", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath, _ := writeTestConfigs(t)
+			app := &cli.App{
+				Flags:          []cli.Flag{&cli.StringFlag{Name: configFlag}},
+				Commands:       []*cli.Command{cmdSetConfig()},
+				ExitErrHandler: func(_ *cli.Context, _ error) {},
+			}
+			err := app.Run([]string{"gnfd-cmd", "--config", configPath, "set", tt.key, tt.value})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			config, err := parseConfigFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.get(config); got != tt.want {
+				t.Errorf("got %q, expected %q", got, tt.want)
+			}
+			// the other settings are kept
+			if config.RpcAddr != "http://file:26750" || config.Profiles["staging"].ChainId != "greenfield_2-1" {
+				t.Errorf("the other settings are changed: %+v", config)
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/bnb-chain/greenfield-go-sdk/client"
 	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	storageTypes "github.com/bnb-chain/greenfield/x/storage/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/urfave/cli/v2"
 )
 
@@ -34,17 +37,25 @@ func cmdDelObject() *cli.Command {
 		Usage:     "delete existed object",
 		ArgsUsage: "OBJECT-URL",
 		Description: `
-Send a deleteObject txn to greenfield chain
+Send a deleteObject txn to greenfield chain. If the recursive flag is set, all the objects under the prefix
+are deleted by multi-msg txns and the number of msgs in one txn can be set by the batchSize flag.
 
 Examples:
 # Delete an existed object called gnfd-object
-$ gnfd-cmd object rm gnfd://gnfd-bucket/gnfd-object`,
+$ gnfd-cmd object rm gnfd://gnfd-bucket/gnfd-object
+# Delete all the objects under the prefix, 100 objects in one txn
+$ gnfd-cmd object rm --recursive --batchSize 100 gnfd://gnfd-bucket/gnfd-prefix`,
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  recursiveFlag,
 				Value: false,
 				Usage: "performed on all files or objects under the specified directory or prefix in a recursive way",
 			},
+			&cli.IntFlag{
+				Name:  batchSizeFlag,
+				Value: defaultDeleteBatchSize,
+				Usage: "the max number of deleteObject msgs sent in one txn when deleting in a recursive way",
+			},
 		},
 	}
 }
@@ -85,13 +96,12 @@ func deleteBucket(ctx *cli.Context) error {
 
 	_, err = client.HeadBucket(c, bucketName)
 	if err != nil {
-		fmt.Printf("bucket %s not exist or already deleted\n", bucketName)
+		return toCmdErr(fmt.Errorf("bucket %s not exist or already deleted: %v", bucketName, err))
 	}
 
 	txnHash, err := client.DeleteBucket(c, bucketName, sdktypes.DeleteBucketOption{TxOpts: &TxnOptionWithSyncMode})
 	if err != nil {
-		fmt.Println("delete bucket error:", err.Error())
-		return nil
+		return toCmdErr(err)
 	}
 
 	err = waitTxnStatus(client, c, txnHash, "DeleteBucket")
@@ -99,7 +109,10 @@ func deleteBucket(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Printf("delete_bucket: %s \ntransaction hash: %s\n", bucketName, txnHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "delete_bucket", Resource: bucketName, TxnHash: txnHash})
+	}
+	fmt.Fprintf(infoWriter, "delete_bucket: %s \ntransaction hash: %s\n", bucketName, txnHash)
 	return nil
 }
 
@@ -148,29 +161,34 @@ func deleteObject(ctx *cli.Context) error {
 			if !strings.HasSuffix(prefixName, "/") {
 				prefixName = objectName + "/"
 			}
-			err = deleteObjectByPage(client, c, bucketName, prefixName)
+			err = deleteObjectByPage(client, c, bucketName, prefixName, ctx.Int(batchSizeFlag))
 		} else {
 			// list all the objects in the bucket and delete them
-			err = deleteObjectByPage(client, c, bucketName, prefixName)
+			err = deleteObjectByPage(client, c, bucketName, prefixName, ctx.Int(batchSizeFlag))
 		}
 		if err != nil {
 			return toCmdErr(err)
 		}
 
 	} else {
-		deleteObjectAndWaitTxn(client, c, bucketName, objectName)
+		if err = deleteObjectAndWaitTxn(client, c, bucketName, objectName); err != nil {
+			return toCmdErr(err)
+		}
 	}
 
 	return nil
 }
 
-func deleteObjectByPage(cli client.IClient, c context.Context, bucketName, prefixName string) error {
+func deleteObjectByPage(cli client.IClient, c context.Context, bucketName, prefixName string, batchSize int) error {
 	var (
 		listResult        sdktypes.ListObjectsResult
 		continuationToken string
 		err               error
+		deletedNum        int
+		failedNum         int
 	)
 
+	operator := cli.MustGetDefaultAccount().GetAddress()
 	for {
 		listResult, err = cli.ListObjects(c, bucketName, sdktypes.ListObjectsOptions{ShowRemovedObject: false,
 			MaxKeys:           defaultMaxKey,
@@ -180,10 +198,41 @@ func deleteObjectByPage(cli client.IClient, c context.Context, bucketName, prefi
 			return toCmdErr(err)
 		}
 
-		// TODO use one txn to broadcast multi delete object messages
-		for _, object := range listResult.Objects {
-			// no need to return err if some objects delete failed
-			deleteObjectAndWaitTxn(cli, c, bucketName, object.ObjectInfo.ObjectName)
+		msgs := make([][]sdk.Msg, len(listResult.Objects))
+		for i, object := range listResult.Objects {
+			msgs[i] = []sdk.Msg{storageTypes.NewMsgDeleteObject(operator, bucketName, object.ObjectInfo.ObjectName)}
+		}
+
+		// continue deleting the other objects if some objects delete failed
+		results := broadcastMsgsInBatch(cli, c, msgs, batchSize, "DeleteObject")
+		for i, result := range results {
+			objectName := listResult.Objects[i].ObjectInfo.ObjectName
+			if errors.Is(result.Err, ErrTxnNotConfirmed) {
+				// the txn may have been committed, the object is deleted if it is not found on chain
+				if _, headErr := cli.HeadObject(c, bucketName, objectName); isNoSuchObjectErr(headErr) {
+					result.Err = nil
+				}
+			}
+			if result.Err != nil {
+				failedNum++
+			} else {
+				deletedNum++
+			}
+			if isStructuredOutput() {
+				record := txnRecord{Action: "delete_object", Resource: bucketName + "/" + objectName, TxnHash: result.TxnHash}
+				if result.Err != nil {
+					record.Error = result.Err.Error()
+				}
+				if err = printRecord(record); err != nil {
+					return err
+				}
+				continue
+			}
+			if result.Err != nil {
+				fmt.Fprintf(infoWriter, "failed to delete object %s, err:%v\n", objectName, result.Err)
+				continue
+			}
+			fmt.Fprintf(infoWriter, "delete: %s, transaction hash: %s\n", objectName, result.TxnHash)
 		}
 
 		if !listResult.IsTruncated {
@@ -192,23 +241,41 @@ func deleteObjectByPage(cli client.IClient, c context.Context, bucketName, prefi
 
 		continuationToken = listResult.NextContinuationToken
 	}
+
+	if !isStructuredOutput() {
+		fmt.Fprintf(infoWriter, "%d objects deleted, %d objects failed\n", deletedNum, failedNum)
+	}
+	if failedNum > 0 {
+		return fmt.Errorf("%d objects failed to be deleted", failedNum)
+	}
+	return nil
+}
+
+func deleteObjectAndWaitTxn(cli client.IClient, c context.Context, bucketName, objectName string) error {
+	txnHash, err := deleteObjectAndWait(cli, c, bucketName, objectName)
+	if err != nil {
+		return err
+	}
+
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "delete_object", Resource: bucketName + "/" + objectName, TxnHash: txnHash})
+	}
+	fmt.Fprintf(infoWriter, "delete: %s\n", objectName)
 	return nil
 }
 
-func deleteObjectAndWaitTxn(cli client.IClient, c context.Context, bucketName, objectName string) {
+// deleteObjectAndWait send the deleteObject msg and wait for the txn to be committed, the txn hash is returned
+func deleteObjectAndWait(cli client.IClient, c context.Context, bucketName, objectName string) (string, error) {
 	txnHash, err := cli.DeleteObject(c, bucketName, objectName, sdktypes.DeleteObjectOption{TxOpts: &TxnOptionWithSyncMode})
 	if err != nil {
-		fmt.Printf("failed to delele object %s err:%v\n", objectName, err)
-		return
+		return "", fmt.Errorf("failed to delele object %s err:%v", objectName, err)
 	}
 
 	err = waitTxnStatus(cli, c, txnHash, "DeleteObject")
 	if err != nil {
-		fmt.Printf("failed to query the txn of deleting object %s, err:%v\n", objectName, err)
-		return
+		return txnHash, fmt.Errorf("failed to query the txn of deleting object %s, err:%v", objectName, err)
 	}
-
-	fmt.Printf("delete: %s\n", objectName)
+	return txnHash, nil
 }
 
 // deleteGroup send the deleteGroup msg to greenfield
@@ -239,6 +306,9 @@ func deleteGroup(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Printf("delete_group: %s \ntransaction hash: %s\n", groupName, txnHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "delete_group", Resource: groupName, TxnHash: txnHash})
+	}
+	fmt.Fprintf(infoWriter, "delete_group: %s \ntransaction hash: %s\n", groupName, txnHash)
 	return nil
 }
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"cosmossdk.io/math"
@@ -59,22 +58,21 @@ $ gnfd-cmd group update --groupOwner 0x.. --addMembers 0x.. group-name`,
 			&cli.StringFlag{
 				Name:  addMemberFlag,
 				Value: "",
-				Usage: "indicate the init member addr string list, input like addr1,addr2,addr3",
+				Usage: "indicate the member list of the addresses or the names of the local accounts, input like addr1,addr2,name3",
 			},
 			&cli.StringFlag{
 				Name:  removeMemberFlag,
 				Value: "",
-				Usage: "indicate the init member addr string list, input like addr1,addr2,addr3",
+				Usage: "indicate the member list of the addresses or the names of the local accounts, input like addr1,addr2,name3",
 			},
 			&cli.StringFlag{
 				Name:  groupOwnerFlag,
 				Value: "",
 				Usage: "need set the owner address if you are not the owner of the group",
 			},
-			&cli.Int64Flag{
+			&cli.StringFlag{
 				Name:     groupMemberExpireFlag,
-				Value:    0,
-				Usage:    "set the expire timestamp for the addMember, it will apply to all the add members",
+				Usage:    "set the expire time for the addMember, it will apply to all the add members. It can be a unix timestamp, RFC3339 or in the format of the timeFormat flag",
 				Required: false,
 			},
 		},
@@ -93,12 +91,13 @@ renew expiration time of a list of group members
 You need also set group owner using --groupOwner if you are not the owner of the group.
 
 Examples:
-$ gnfd-cmd group renew --groupOwner 0x.. --renewMembers 0x..  --expireTime 1691569957 group-name`,
+$ gnfd-cmd group renew --groupOwner 0x.. --renewMembers 0x..  --expireTime 1691569957 group-name
+$ gnfd-cmd --timezone UTC group renew --groupOwner 0x.. --renewMembers 0x..  --expireTime "2024-08-09 08:32:37" group-name`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     renewMemberFlag,
 				Value:    "",
-				Usage:    "indicate the init member addr string list, input like addr1,addr2,addr3",
+				Usage:    "indicate the member list of the addresses or the names of the local accounts, input like addr1,addr2,name3",
 				Required: true,
 			},
 			&cli.StringFlag{
@@ -107,10 +106,9 @@ $ gnfd-cmd group renew --groupOwner 0x.. --renewMembers 0x..  --expireTime 16915
 				Usage:    "need set the owner address if you are not the owner of the group",
 				Required: false,
 			},
-			&cli.Int64Flag{
+			&cli.StringFlag{
 				Name:     groupMemberExpireFlag,
-				Value:    0,
-				Usage:    "set the expire timestamp for the addMember, it will apply to all the add members",
+				Usage:    "set the expire time for the addMember, it will apply to all the add members. It can be a unix timestamp, RFC3339 or in the format of the timeFormat flag",
 				Required: false,
 			},
 		},
@@ -295,6 +293,9 @@ func setTagForGroup(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "set_tag", Resource: grn.String(), TxnHash: txnHash})
+	}
 	return nil
 }
 
@@ -339,13 +340,19 @@ func createGroup(ctx *cli.Context) error {
 	if err == nil {
 		info, err := client.HeadGroup(c, groupName, groupOwner)
 		if err == nil {
-			fmt.Printf("make_group: %s \ntransaction hash: %s\ngroup id: %s \n",
+			if isStructuredOutput() {
+				return printRecord(txnRecord{Action: "make_group", Resource: groupName, ResourceID: info.Id.String(), TxnHash: txnHash})
+			}
+			fmt.Fprintf(infoWriter, "make_group: %s \ntransaction hash: %s\ngroup id: %s \n",
 				groupName, txnHash, info.Id.String())
 			return nil
 		}
 	}
 
-	fmt.Printf("make_group: %s \ntransaction hash: %s\n", groupName, txnHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "make_group", Resource: groupName, TxnHash: txnHash})
+	}
+	fmt.Fprintf(infoWriter, "make_group: %s \ntransaction hash: %s\n", groupName, txnHash)
 	return nil
 }
 
@@ -360,25 +367,18 @@ func updateGroupMember(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	addMembersInfo := ctx.String(addMemberFlag)
-	removeMembersInfo := ctx.String(removeMemberFlag)
-
-	if addMembersInfo == "" && removeMembersInfo == "" {
+	if ctx.String(addMemberFlag) == "" && ctx.String(removeMemberFlag) == "" {
 		return toCmdErr(errors.New("fail to get members to update"))
 	}
 
-	var addGroupMembers []string
-	var removeGroupMembers []string
-	if strings.Contains(addMembersInfo, ",") {
-		addGroupMembers = strings.Split(addMembersInfo, ",")
-	} else if addMembersInfo != "" {
-		addGroupMembers = []string{addMembersInfo}
+	// the members can be the addresses or the names of the local accounts
+	addGroupMembers, err := getAddressListFlag(ctx, addMemberFlag)
+	if err != nil {
+		return newCmdError(exitCodeUsage, err)
 	}
-
-	if strings.Contains(removeMembersInfo, ",") {
-		removeGroupMembers = strings.Split(removeMembersInfo, ",")
-	} else if removeMembersInfo != "" {
-		removeGroupMembers = []string{removeMembersInfo}
+	removeGroupMembers, err := getAddressListFlag(ctx, removeMemberFlag)
+	if err != nil {
+		return newCmdError(exitCodeUsage, err)
 	}
 
 	groupOwner, err := getGroupOwner(ctx)
@@ -394,7 +394,10 @@ func updateGroupMember(ctx *cli.Context) error {
 		return toCmdErr(ErrGroupNotExist)
 	}
 
-	expireTimestamp := ctx.Int64(groupMemberExpireFlag)
+	expireTimestamp, err := getTimeFlag(ctx, groupMemberExpireFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 
 	if expireTimestamp != 0 && expireTimestamp < time.Now().Unix() {
 		return toCmdErr(errors.New("expire stamp should be more than" + strconv.Itoa(int(time.Now().Unix()))))
@@ -424,7 +427,10 @@ func updateGroupMember(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Printf("update_group: %s \ntransaction hash: %s\n", groupName, txnHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "update_group", Resource: groupName, TxnHash: txnHash})
+	}
+	fmt.Fprintf(infoWriter, "update_group: %s \ntransaction hash: %s\n", groupName, txnHash)
 	return nil
 }
 
@@ -439,16 +445,13 @@ func renewGroupMember(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	renewMembersInfo := ctx.String(renewMemberFlag)
-	if renewMembersInfo == "" {
+	if ctx.String(renewMemberFlag) == "" {
 		return toCmdErr(errors.New("fail to get members to renew"))
 	}
 
-	var renewGroupMembers []string
-	if strings.Contains(renewMembersInfo, ",") {
-		renewGroupMembers = strings.Split(renewMembersInfo, ",")
-	} else if renewMembersInfo != "" {
-		renewGroupMembers = []string{renewMembersInfo}
+	renewGroupMembers, err := getAddressListFlag(ctx, renewMemberFlag)
+	if err != nil {
+		return newCmdError(exitCodeUsage, err)
 	}
 
 	groupOwner, err := getGroupOwner(ctx)
@@ -456,7 +459,10 @@ func renewGroupMember(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	expireTimestamp := ctx.Int64(groupMemberExpireFlag)
+	expireTimestamp, err := getTimeFlag(ctx, groupMemberExpireFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	if expireTimestamp < time.Now().Unix() {
 		return toCmdErr(errors.New("expire stamp should be more than" + strconv.Itoa(int(time.Now().Unix()))))
 	} else if expireTimestamp == 0 {
@@ -490,7 +496,10 @@ func renewGroupMember(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Printf("renew_group: %s \ntransaction hash: %s\n", groupName, txnHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "renew_group", Resource: groupName, TxnHash: txnHash})
+	}
+	fmt.Fprintf(infoWriter, "renew_group: %s \ntransaction hash: %s\n", groupName, txnHash)
 	return nil
 }
 
@@ -527,7 +536,9 @@ func listGroupMember(ctx *cli.Context) error {
 			return toCmdErr(err)
 		}
 
-		printListMemberResult(memberList)
+		if err = printListMemberResult(groupName, memberList); err != nil {
+			return toCmdErr(err)
+		}
 		memberNum := len(memberList.Groups)
 		if memberNum < maxListMemberNum {
 			break
@@ -563,7 +574,9 @@ func listGroup(ctx *cli.Context) error {
 			return toCmdErr(err)
 		}
 
-		printListGroupResult(groupList)
+		if err = printListGroupResult(groupList); err != nil {
+			return toCmdErr(err)
+		}
 		memberNum := len(groupList.Groups)
 		if memberNum < maxListMemberNum {
 			break
@@ -599,7 +612,9 @@ func listBelongGroup(ctx *cli.Context) error {
 			return toCmdErr(err)
 		}
 
-		printListGroupResult(groupList)
+		if err = printListGroupResult(groupList); err != nil {
+			return toCmdErr(err)
+		}
 		memberNum := len(groupList.Groups)
 		if memberNum < maxListMemberNum {
 			break
@@ -612,44 +627,99 @@ func listBelongGroup(ctx *cli.Context) error {
 	return nil
 }
 
-func printListMemberResult(listResult *sdktypes.GroupMembersResult) {
-	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds  \n", len(iso8601DateFormat), operatorAddressLen, len(iso8601DateFormat))
-	fmt.Printf(format, "create-time", "member", "expire-time")
+// groupMemberRecord is the record of the members listed by "group ls-member"
+type groupMemberRecord struct {
+	GroupName  string `json:"group_name"`
+	Member     string `json:"member"`
+	CreateTime string `json:"create_time,omitempty"`
+	ExpireTime string `json:"expire_time,omitempty"`
+}
+
+// groupListRecord is the record of the groups listed by "group ls" and "group ls-belong"
+type groupListRecord struct {
+	GroupName  string `json:"group_name"`
+	GroupID    string `json:"group_id"`
+	Owner      string `json:"owner"`
+	Extra      string `json:"extra,omitempty"`
+	CreateTime string `json:"create_time"`
+}
+
+func printListMemberResult(groupName string, listResult *sdktypes.GroupMembersResult) error {
+	if isStructuredOutput() {
+		for _, member := range listResult.Groups {
+			if member.Removed {
+				continue
+			}
+			expireTime, err := strconv.ParseInt(member.ExpirationTime, 10, 64)
+			if err != nil {
+				expireTime = 0
+			}
+			if err = printRecord(groupMemberRecord{
+				GroupName:  groupName,
+				Member:     member.AccountID,
+				CreateTime: formatRecordTime(member.CreateTime),
+				ExpireTime: formatRecordTime(expireTime),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds  \n", timeColumnWidth(), operatorAddressLen, timeColumnWidth())
+	fmt.Fprintf(infoWriter, format, "create-time", "member", "expire-time")
 
 	for _, member := range listResult.Groups {
 		if member.Removed {
 			continue
 		}
 
-		location, _ := time.LoadLocation("Asia/Shanghai")
-		t := time.Unix(member.CreateTime, 0).In(location)
-
 		expireTime, err := strconv.ParseInt(member.ExpirationTime, 10, 64)
 		if err != nil {
 			expireTime = 0
 		}
 
-		fmt.Printf(format, t.Format(iso8601DateFormat), member.AccountID, time.Unix(expireTime, 0).In(location).Format(iso8601DateFormat))
+		fmt.Fprintf(infoWriter, format, formatTime(member.CreateTime), member.AccountID, formatTime(expireTime))
 	}
+	return nil
 }
 
-func printListGroupResult(listResult *sdktypes.GroupsResult) {
-	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds  \n", len(iso8601DateFormat)+3, 20, 10)
-	fmt.Printf(format, "create-time", "group-name", "id")
+func printListGroupResult(listResult *sdktypes.GroupsResult) error {
+	if isStructuredOutput() {
+		for _, group := range listResult.Groups {
+			if group.Removed {
+				continue
+			}
+			if err := printRecord(groupListRecord{
+				GroupName:  group.Group.GroupName,
+				GroupID:    strconv.FormatUint(group.Group.Id.Uint64(), 10),
+				Owner:      group.Group.Owner,
+				Extra:      group.Group.Extra,
+				CreateTime: formatRecordTime(group.CreateTime),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds  \n", timeColumnWidth()+3, 20, 10)
+	fmt.Fprintf(infoWriter, format, "create-time", "group-name", "id")
 
 	for _, group := range listResult.Groups {
 		if group.Removed {
 			continue
 		}
-		location, _ := time.LoadLocation("Asia/Shanghai")
-		t := time.Unix(group.CreateTime, 0).In(location)
-
-		fmt.Printf(format, t.Format(iso8601DateFormat), group.Group.GroupName, strconv.FormatUint(group.Group.Id.Uint64(), 10))
+		fmt.Fprintf(infoWriter, format, formatTime(group.CreateTime), group.Group.GroupName, strconv.FormatUint(group.Group.Id.Uint64(), 10))
 	}
+	return nil
 }
 
 func getGroupOwner(ctx *cli.Context) (string, error) {
-	groupOwnerAddrStr := ctx.String(groupOwnerFlag)
+	groupOwnerAddrStr, err := getAddressFlag(ctx, groupOwnerFlag)
+	if err != nil {
+		return "", err
+	}
 
 	if groupOwnerAddrStr != "" {
 		return groupOwnerAddrStr, nil
@@ -687,6 +757,9 @@ func mirrorGroup(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Printf("mirror_group: %s \ntransaction hash: %s\n", groupName, txResp.TxHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "mirror_group", Resource: groupName, ResourceID: id.String(), TxnHash: txResp.TxHash})
+	}
+	fmt.Fprintf(infoWriter, "mirror_group: %s \ntransaction hash: %s\n", groupName, txResp.TxHash)
 	return nil
 }
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/bnb-chain/greenfield-go-sdk/client"
+	storageTypes "github.com/bnb-chain/greenfield/x/storage/types"
 	"github.com/urfave/cli/v2"
 )
 
@@ -24,6 +31,22 @@ $ gnfd-cmd object get-hash /home/test.text `,
 	}
 }
 
+// hashRecord is the record of the integrity hash computed by "object get-hash"
+type hashRecord struct {
+	FilePath        string   `json:"file_path"`
+	Size            int64    `json:"size"`
+	PrimaryHash     string   `json:"primary_hash"`
+	SecondaryHashes []string `json:"secondary_hashes"`
+}
+
+// verifyRecord is the record of the result of "object verify"
+type verifyRecord struct {
+	BucketName string `json:"bucket_name"`
+	ObjectName string `json:"object_name"`
+	FilePath   string `json:"file_path"`
+	Matched    bool   `json:"matched"`
+}
+
 func computeHashRoot(ctx *cli.Context) error {
 	// read the local file payload to be uploaded
 	filePath := ctx.Args().Get(0)
@@ -53,17 +76,155 @@ func computeHashRoot(ctx *cli.Context) error {
 
 	hashes, size, _, err := gnfdClient.ComputeHashRoots(fReader, false)
 	if err != nil {
-		fmt.Println("compute hash root fail:", err.Error())
-		return toCmdErr(err)
+		return toCmdErr(fmt.Errorf("compute hash root fail: %v", err))
 	}
 
-	fmt.Printf("the primary sp hash root: \n%s\n%s\n", hex.EncodeToString(hashes[0]), "the secondary sp hash list:")
+	if isStructuredOutput() {
+		record := hashRecord{FilePath: filePath, Size: size, PrimaryHash: hex.EncodeToString(hashes[0])}
+		for _, hash := range hashes[1:] {
+			record.SecondaryHashes = append(record.SecondaryHashes, hex.EncodeToString(hash))
+		}
+		return printRecord(record)
+	}
+
+	fmt.Fprintf(infoWriter, "the primary sp hash root: \n%s\n%s\n", hex.EncodeToString(hashes[0]), "the secondary sp hash list:")
 
 	for _, hash := range hashes[1:] {
-		fmt.Println(hex.EncodeToString(hash))
+		fmt.Fprintln(infoWriter, hex.EncodeToString(hash))
 	}
 
-	fmt.Println("file size:", size)
+	fmt.Fprintln(infoWriter, "file size:", size)
 
 	return nil
 }
+
+// cmdVerifyObject verify the local file with the checksums of the object on chain
+func cmdVerifyObject() *cli.Command {
+	return &cli.Command{
+		Name:      "verify",
+		Action:    verifyObject,
+		Usage:     "verify the integrity of the local file with the checksums of the object",
+		ArgsUsage: "OBJECT-URL filePath",
+		Description: `
+Compute the integrity hash of the local file, including the primary sp hash root and the secondary sp hash roots,
+and compare them with the checksums of the object stored on chain. The command fails if they are not the same.
+
+Examples:
+$ gnfd-cmd object verify gnfd://gnfd-bucket/gnfd-object ./file.txt`,
+	}
+}
+
+func verifyObject(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return toCmdErr(fmt.Errorf("args number should be 2"))
+	}
+
+	bucketName, objectName, err := ParseBucketAndObject(ctx.Args().Get(0))
+	if err != nil {
+		return toCmdErr(err)
+	}
+	filePath := ctx.Args().Get(1)
+
+	gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: true})
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	c, cancelVerify := context.WithCancel(globalContext)
+	defer cancelVerify()
+
+	if err = verifyFileWithObject(c, gnfdClient, bucketName, objectName, filePath); err != nil {
+		return toCmdErr(err)
+	}
+	if isStructuredOutput() {
+		return printRecord(verifyRecord{BucketName: bucketName, ObjectName: objectName, FilePath: filePath, Matched: true})
+	}
+	fmt.Fprintf(infoWriter, "the integrity hash of %s matches the checksums of object %s\n", filePath, objectName)
+	return nil
+}
+
+// verifyFileWithObject compare the integrity hash of the local file with the checksums of the object on chain
+func verifyFileWithObject(c context.Context, gnfdClient client.IClient, bucketName, objectName, filePath string) error {
+	objectDetail, err := gnfdClient.HeadObject(c, bucketName, objectName)
+	if err != nil {
+		return headObjectErr(bucketName, objectName, err)
+	}
+
+	hashes, size, err := computeHashRootsOfFile(gnfdClient, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to compute the integrity hash of %s: %v", filePath, err)
+	}
+
+	if uint64(size) != objectDetail.ObjectInfo.PayloadSize {
+		return fmt.Errorf("the size of %s is %d, but the payload size of object %s is %d", filePath, size, objectName, objectDetail.ObjectInfo.PayloadSize)
+	}
+
+	if !isChecksumsEqual(hashes, objectDetail.ObjectInfo.Checksums) {
+		return fmt.Errorf("the integrity hash of %s does not match the checksums of object %s", filePath, objectName)
+	}
+	return nil
+}
+
+// fileHashes is the integrity hash of a local file, it is computed once and reused to compare and create the object
+type fileHashes struct {
+	checksums      [][]byte
+	size           int64
+	redundancyType storageTypes.RedundancyType
+}
+
+// computeFileHashes compute the integrity hash of the local file and the redundancy type of the object created by it
+func computeFileHashes(gnfdClient client.IClient, filePath string) (*fileHashes, error) {
+	fReader, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fReader.Close()
+
+	checksums, size, redundancyType, err := gnfdClient.ComputeHashRoots(fReader, false)
+	if err != nil {
+		return nil, err
+	}
+	return &fileHashes{checksums: checksums, size: size, redundancyType: redundancyType}, nil
+}
+
+// computeHashRootsOfFile compute the integrity hash of the local file, the result can be compared with the checksums of object
+func computeHashRootsOfFile(gnfdClient client.IClient, filePath string) ([][]byte, int64, error) {
+	hashes, err := computeFileHashes(gnfdClient, filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hashes.checksums, hashes.size, nil
+}
+
+// isChecksumsEqual check if the integrity hash of the local file is the same as the checksums on chain
+func isChecksumsEqual(localHashes, onChainChecksums [][]byte) bool {
+	if len(localHashes) != len(onChainChecksums) {
+		return false
+	}
+	for i := range localHashes {
+		if !bytes.Equal(localHashes[i], onChainChecksums[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// computeFileFingerprint compute the fingerprint of the file by the sha256 of the size and the whole content.
+// It is compared when the modified time or the size of the file is changed, so that a same-size edit is detected.
+func computeFileFingerprint(filePath string, size int64) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	sizeBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(sizeBytes, uint64(size))
+	hash.Write(sizeBytes)
+
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	storageTypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func TestIsChecksumsEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		local    [][]byte
+		onChain  [][]byte
+		expected bool
+	}{
+		{name: "equal", local: [][]byte{{1, 2}, {3}}, onChain: [][]byte{{1, 2}, {3}}, expected: true},
+		{name: "empty", expected: true},
+		{name: "different piece", local: [][]byte{{1, 2}, {3}}, onChain: [][]byte{{1, 2}, {4}}},
+		{name: "different length", local: [][]byte{{1, 2}}, onChain: [][]byte{{1, 2}, {3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isChecksumsEqual(tt.local, tt.onChain); got != tt.expected {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHeadObjectErr(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		notFound bool
+		code     int
+	}{
+		{name: "no such object", err: errors.New("rpc error: code = Unknown desc = No such object: unknown request"), notFound: true, code: exitCodeNotFound},
+		{name: "connection refused", err: errors.New("dial tcp 127.0.0.1:26750: connect: connection refused"), code: exitCodeNetworkError},
+		{name: "unknown", err: errors.New("something went wrong"), code: exitCodeGeneral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := headObjectErr("bucket", "object", tt.err)
+			if errors.Is(err, ErrObjectNotExist) != tt.notFound {
+				t.Errorf("got %v, expected the object not found %v", err, tt.notFound)
+			}
+			if code := classifyCmdErr(err).Code; code != tt.code {
+				t.Errorf("got the exit code %d, expected %d", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestVerifyFileWithObject(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(filePath, []byte("content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		objectInfo *storageTypes.ObjectInfo
+		headErr    error
+		filePath   string
+		wantErr    string
+	}{
+		{name: "matched", objectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: [][]byte{[]byte("content")}}},
+		{name: "another size", objectInfo: &storageTypes.ObjectInfo{PayloadSize: 8, Checksums: [][]byte{[]byte("content")}}, wantErr: "payload size"},
+		{name: "another content", objectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: [][]byte{[]byte("changed")}}, wantErr: "does not match"},
+		{name: "missing object", headErr: errors.New("No such object"), wantErr: ErrObjectNotExist.Error()},
+		{name: "missing file", objectInfo: &storageTypes.ObjectInfo{PayloadSize: 7}, filePath: filePath + ".missing", wantErr: "failed to compute"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gnfdClient := newFakeHashClient()
+			gnfdClient.headObject = func(bucketName, objectName string) (*sdktypes.ObjectDetail, error) {
+				return &sdktypes.ObjectDetail{ObjectInfo: tt.objectInfo}, tt.headErr
+			}
+			path := filePath
+			if tt.filePath != "" {
+				path = tt.filePath
+			}
+
+			err := verifyFileWithObject(globalContext, gnfdClient, "bucket", "object", path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got the error %v, expected %q", err, tt.wantErr)
+			}
+		})
+	}
+}
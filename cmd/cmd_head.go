@@ -127,11 +127,14 @@ func headObject(ctx *cli.Context) error {
 
 	objectDetail, err := client.HeadObject(c, bucketName, objectName)
 	if err != nil {
-		fmt.Println("no such object")
-		return nil
+		return toCmdErr(fmt.Errorf("no such object: %v", err))
 	}
 
-	fmt.Println("latest object info:")
+	if isStructuredOutput() {
+		return printRecord(objectDetail)
+	}
+
+	fmt.Fprintln(infoWriter, "latest object info:")
 	if format := ctx.String(formatFlag); format != "" {
 		if format == defaultFormat {
 			parseObjectInfo(objectDetail)
@@ -160,11 +163,14 @@ func headBucket(ctx *cli.Context) error {
 
 	bucketInfo, err := client.HeadBucket(c, bucketName)
 	if err != nil {
-		fmt.Println("no such bucket")
-		return nil
+		return toCmdErr(fmt.Errorf("no such bucket: %v", err))
+	}
+
+	if isStructuredOutput() {
+		return printRecord(bucketInfo)
 	}
 
-	fmt.Println("latest bucket info:")
+	fmt.Fprintln(infoWriter, "latest bucket info:")
 	if format := ctx.String(formatFlag); format != "" {
 		if format == defaultFormat {
 			parseBucketInfo(bucketInfo)
@@ -198,16 +204,19 @@ func headGroup(ctx *cli.Context) error {
 
 	groupInfo, err := client.HeadGroup(c, groupName, groupOwner)
 	if err != nil {
-		fmt.Println("no such group")
-		return nil
+		return toCmdErr(fmt.Errorf("no such group: %v", err))
 	}
 
-	fmt.Println("latest group info:")
+	if isStructuredOutput() {
+		return printRecord(groupInfo)
+	}
+
+	fmt.Fprintln(infoWriter, "latest group info:")
 	if format := ctx.String(formatFlag); format != "" {
 		if format == defaultFormat {
 			infoStr := strings.Split(groupInfo.String(), " ")
 			for _, info := range infoStr {
-				fmt.Println(info)
+				fmt.Fprintln(infoWriter, info)
 			}
 		} else if format == jsonFormat {
 			parseByJsonFormat(groupInfo)
@@ -243,10 +252,12 @@ func headGroupMember(ctx *cli.Context) error {
 
 	exist := client.HeadGroupMember(c, groupName, groupOwner, headMember)
 	if !exist {
-		fmt.Println("the user does not exist in the group")
-		return nil
+		return toCmdErr(fmt.Errorf("the user %s does not exist in the group: %s", headMember, groupName))
 	}
 
-	fmt.Printf("the user %s is a member of the group: %s \n", headMember, groupName)
+	if isStructuredOutput() {
+		return printRecord(groupMemberRecord{GroupName: groupName, Member: headMember})
+	}
+	fmt.Fprintf(infoWriter, "the user %s is a member of the group: %s \n", headMember, groupName)
 	return nil
 }
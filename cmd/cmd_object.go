@@ -40,7 +40,11 @@ Examples:
 # create object and upload file to storage provider, the corresponding object is gnfd-object
 $ gnfd-cmd object put file.txt gnfd://gnfd-bucket/gnfd-object,
 # upload the files inside the folders
-$ gnfd-cmd object put --tags='[{"key":"key1","value":"value1"},{"key":"key2","value":"value2"}]' --recursive folderName gnfd://bucket-name`,
+$ gnfd-cmd object put --tags='[{"key":"key1","value":"value1"},{"key":"key2","value":"value2"}]' --recursive folderName gnfd://bucket-name
+# upload the files inside the folders with 10 objects uploaded at the same time
+$ gnfd-cmd object put --recursive --concurrency 10 folderName gnfd://bucket-name
+# upload the data read from stdin, the data is spooled to a temp file to compute the hash roots before creating object
+$ tar c dir | gnfd-cmd -p password.txt object put - gnfd://gnfd-bucket/dir.tar`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  secondarySPFlag,
@@ -58,7 +62,8 @@ $ gnfd-cmd object put --tags='[{"key":"key1","value":"value1"},{"key":"key2","va
 					Enum:    []string{publicReadType, privateType, inheritType},
 					Default: inheritType,
 				},
-				Usage: "set visibility of the object",
+				Usage:   "set visibility of the object",
+				EnvVars: []string{objectVisibilityEnv},
 			},
 			&cli.Uint64Flag{
 				Name: partSizeFlag,
@@ -66,6 +71,7 @@ $ gnfd-cmd object put --tags='[{"key":"key1","value":"value1"},{"key":"key2","va
 				Value: 32 * 1024 * 1024,
 				Usage: "indicate the resumable upload 's part size, uploading a large file in multiple parts. " +
 					"The part size is an integer multiple of the segment size.",
+				EnvVars: []string{partSizeEnv},
 			},
 			&cli.BoolFlag{
 				Name:  resumableFlag,
@@ -89,6 +95,23 @@ $ gnfd-cmd object put --tags='[{"key":"key1","value":"value1"},{"key":"key2","va
 				Value: "",
 				Usage: "set one or more tags of the object. The tag value is key-value pairs in json array format. E.g. [{\"key\":\"key1\",\"value\":\"value1\"},{\"key\":\"key2\",\"value\":\"value2\"}]",
 			},
+			&cli.IntFlag{
+				Name:    concurrencyFlag,
+				Value:   1,
+				Usage:   "indicate the number of objects to be uploaded at the same time when uploading a folder with the recursive flag",
+				EnvVars: []string{concurrencyEnv},
+			},
+			&cli.IntFlag{
+				Name:  batchSizeFlag,
+				Value: defaultCreateBatchSize,
+				Usage: "indicate the max number of objects created on chain in one txn when uploading a folder with the recursive flag",
+			},
+			&cli.DurationFlag{
+				Name:    sealTimeoutFlag,
+				Value:   defaultSealTimeout,
+				Usage:   "indicate the max time to wait for the objects to be sealed after uploading a folder with the recursive flag, 0 means no limit",
+				EnvVars: []string{sealTimeoutEnv},
+			},
 		},
 	}
 }
@@ -101,11 +124,21 @@ func cmdGetObj() *cli.Command {
 		Usage:     "download an object",
 		ArgsUsage: "[filePath] OBJECT-URL",
 		Description: `
-Download a specific object from storage provider
+Download a specific object from storage provider.
+With the recursive flag, all the objects under the prefix are downloaded to the local directory,
+the download is run as a task which can be resumed by "task retry".
 
 Examples:
 # download an object payload to file
-$ gnfd-cmd object get gnfd://gnfd-bucket/gnfd-object  file.txt `,
+$ gnfd-cmd object get gnfd://gnfd-bucket/gnfd-object  file.txt
+# download all the objects under the prefix to the local directory
+$ gnfd-cmd object get --recursive --concurrency 10 gnfd://gnfd-bucket/prefix/ ./local-dir
+# download an object by 8 ranges at the same time, run the command again to resume the download if it is interrupted
+$ gnfd-cmd object get --concurrency 8 gnfd://gnfd-bucket/gnfd-object file.txt
+# download an object and verify it with the checksums on chain
+$ gnfd-cmd object get --verify gnfd://gnfd-bucket/gnfd-object file.txt
+# write the object payload to stdout
+$ gnfd-cmd object get gnfd://gnfd-bucket/dir.tar - | tar x`,
 		Flags: []cli.Flag{
 			&cli.Int64Flag{
 				Name:  startOffsetFlag,
@@ -123,6 +156,7 @@ $ gnfd-cmd object get gnfd://gnfd-bucket/gnfd-object  file.txt `,
 				Value: 32 * 1024 * 1024,
 				Usage: "indicate the resumable upload 's part size, uploading a large file in multiple parts. " +
 					"The part size is an integer multiple of the segment size.",
+				EnvVars: []string{partSizeEnv},
 			},
 			&cli.BoolFlag{
 				Name:  resumableFlag,
@@ -136,6 +170,24 @@ $ gnfd-cmd object get gnfd://gnfd-bucket/gnfd-object  file.txt `,
 				Value: "",
 				Usage: "indicate object sp endpoint",
 			},
+			&cli.BoolFlag{
+				Name:  recursiveFlag,
+				Value: false,
+				Usage: "performed on all files or objects under the specified directory or prefix in a recursive way",
+			},
+			&cli.IntFlag{
+				Name:  concurrencyFlag,
+				Value: 1,
+				Usage: "indicate the number of objects to be downloaded at the same time when downloading with the recursive flag, " +
+					"or the number of parts of a single object to be downloaded at the same time. The parallel download of a single object " +
+					"is resumable, the part size is set by the partSize flag",
+				EnvVars: []string{concurrencyEnv},
+			},
+			&cli.BoolFlag{
+				Name:  verifyFlag,
+				Value: false,
+				Usage: "verify the downloaded file with the checksums of the object on chain, the file is removed if they do not match",
+			},
 		},
 	}
 }
@@ -332,6 +384,9 @@ func setTagForObject(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "set_tag", Resource: grn.String(), TxnHash: txnHash})
+	}
 	return nil
 }
 
@@ -349,6 +404,11 @@ func putObject(ctx *cli.Context) error {
 		urlInfo                          string
 	)
 
+	// the passphrase can not be read from the terminal if stdin is used to read the payload
+	if ctx.Args().First() == stdioFileArg && ctx.String(passwordFileFlag) == "" {
+		return toCmdErr(errors.New("the password file should be set by the global --passwordfile flag when uploading the data of stdin"))
+	}
+
 	gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
 	if err != nil {
 		return err
@@ -402,6 +462,7 @@ func putObject(ctx *cli.Context) error {
 				return toCmdErr(errors.New("fail to parse bucket name"))
 			}
 
+			var failedNum int
 			for idx, fileName := range filePathList {
 				nameList := strings.Split(fileName, "/")
 				objectName = nameList[len(nameList)-1]
@@ -411,9 +472,29 @@ func putObject(ctx *cli.Context) error {
 				}
 
 				if err = uploadFile(bucketName, objectName, fileName, urlInfo, ctx, gnfdClient, false, true, objectSize); err != nil {
-					fmt.Println("upload object:", objectName, "err", err)
+					fmt.Fprintln(infoWriter, "upload object:", objectName, "err", err)
+					failedNum++
 				}
-				fmt.Println()
+				fmt.Fprintln(infoWriter)
+			}
+			if failedNum > 0 {
+				return toCmdErr(fmt.Errorf("%d of %d files failed to be uploaded", failedNum, len(filePathList)))
+			}
+		} else if filePathList[0] == stdioFileArg {
+			// upload the data of stdin, the object name must be set in the url
+			urlInfo = ctx.Args().Get(1)
+			bucketName, objectName, err = getObjAndBucketNames(urlInfo)
+			if err != nil {
+				return toCmdErr(errors.New("the object name should be set when uploading the data of stdin"))
+			}
+			tempFilePath, size, spoolErr := spoolStdinToTempFile()
+			if spoolErr != nil {
+				return toCmdErr(spoolErr)
+			}
+			defer os.Remove(tempFilePath)
+
+			if err = uploadFile(bucketName, objectName, tempFilePath, urlInfo, ctx, gnfdClient, false, true, size); err != nil {
+				return toCmdErr(err)
 			}
 		} else {
 			// upload single file
@@ -466,10 +547,10 @@ func uploadFolder(urlInfo string, ctx *cli.Context,
 
 	taskID := uuid.New().String()
 
-	fmt.Println("================================================")
-	fmt.Println("Your batch upload is submitted as a task, task ID is " + taskID)
-	fmt.Println("You can check your task status and progress by using cmd as below:\n\n- List all your tasks: ./gnfd-cmd task ls\n- Check status: ./gnfd-cmd task status --task.id taskID\n- Retry (in case this process is killed accidentally): ./gnfd-cmd task retry --task.id taskID\n- Delete task: ...\n\n>>================================================")
-	fmt.Println("Upload Task building")
+	fmt.Fprintln(infoWriter, "================================================")
+	fmt.Fprintln(infoWriter, "Your batch upload is submitted as a task, task ID is "+taskID)
+	fmt.Fprintln(infoWriter, "You can check your task status and progress by using cmd as below:\n\n- List all your tasks: ./gnfd-cmd task ls\n- Check status: ./gnfd-cmd task status --taskId taskID\n- Retry (in case this process is killed accidentally): ./gnfd-cmd task retry --taskId taskID\n- Delete task: ./gnfd-cmd task delete --taskId taskID\n\n>>================================================")
+	fmt.Fprintln(infoWriter, "Upload Task building")
 
 	taskState := &TaskState{
 		Lock:        new(sync.Mutex),
@@ -478,6 +559,8 @@ func uploadFolder(urlInfo string, ctx *cli.Context,
 		FolderName:  folderName,
 		BucketName:  bucketName,
 		Status:      TaskStatusCreate,
+		CreateTime:  time.Now().Unix(),
+		Type:        TaskTypeUpload,
 	}
 
 	baseDir := filepath.Base(folderName)
@@ -505,12 +588,12 @@ func uploadFolder(urlInfo string, ctx *cli.Context,
 			}
 			subFolderName := path[index:] + "/"
 			utj := &UploadTaskObject{
-				BucketName:         bucketName,
-				ObjectName:         subFolderName,
-				FilePath:           path,
-				UploadSingleFolder: true,
-				ObjectSize:         0,
-				Status:             TaskObjectStatusWaitForUpload,
+				BucketName: bucketName,
+				ObjectName: subFolderName,
+				FilePath:   path,
+				IsFolder:   true,
+				ObjectSize: 0,
+				Status:     TaskObjectStatusWaitForUpload,
 			}
 			taskState.ObjectState[objectIndex] = utj
 			objectIndex++
@@ -523,13 +606,19 @@ func uploadFolder(urlInfo string, ctx *cli.Context,
 	}
 	// upload folder
 	for id, info := range fileInfos {
+		fingerprint, fingerprintErr := computeFileFingerprint(filePaths[id], info.Size())
+		if fingerprintErr != nil {
+			return fingerprintErr
+		}
 		utj := &UploadTaskObject{
-			BucketName:         bucketName,
-			ObjectName:         objectNames[id],
-			FilePath:           filePaths[id],
-			UploadSingleFolder: false,
-			ObjectSize:         info.Size(),
-			Status:             TaskObjectStatusWaitForUpload,
+			BucketName:  bucketName,
+			ObjectName:  objectNames[id],
+			FilePath:    filePaths[id],
+			IsFolder:    false,
+			ObjectSize:  info.Size(),
+			Status:      TaskObjectStatusWaitForUpload,
+			ModTime:     info.ModTime().UnixNano(),
+			Fingerprint: fingerprint,
 		}
 		taskState.ObjectState[objectIndex] = utj
 		objectIndex++
@@ -558,109 +647,395 @@ func uploadFolder(urlInfo string, ctx *cli.Context,
 		taskState.Flag.Visibility = visibilityTypeVal
 	}
 
-	taskFileName := fmt.Sprintf("/.%s/state", taskID)
-	taskFilePath := filepath.Join(homeDir, taskFileName)
+	return uploadFolderByTask(ctx, homeDir, gnfdClient, taskState, ctx.Int(concurrencyFlag), ctx.Int(batchSizeFlag))
+}
 
-	content, err := json.Marshal(taskState)
-	if err != nil {
+// uploadFolderByTask upload the objects of the task, the objects are created on chain by multi-msg txns
+// before uploading the payloads, and at most concurrency objects are uploaded at the same time
+func uploadFolderByTask(ctx *cli.Context, homeDir string, gnfdClient client.IClient, taskState *TaskState, concurrency, batchSize int) error {
+	// the state transitions are recorded by the journal of the task, so the task can be resumed after a crash
+	if err := taskState.openJournal(homeDir); err != nil {
 		return toCmdErr(err)
 	}
 
-	err = createAndWriteFile(taskFilePath, content)
-	if err != nil {
-		return err
-	}
-
-	return uploadFolderByTask(ctx, homeDir, gnfdClient, taskState)
-}
+	createObjectsByTask(gnfdClient, taskState, batchSize)
 
-func uploadFolderByTask(ctx *cli.Context, homeDir string, gnfdClient client.IClient, taskState *TaskState) error {
-	signalCtx, cancel := context.WithCancel(ctx.Context)
-	go stateSync(signalCtx, homeDir, taskState)
 	sealSignal := make(chan int)
-	go sealChecker(ctx, taskState, gnfdClient, sealSignal)
+	uploadDone := make(chan struct{})
+	go sealChecker(ctx, taskState, gnfdClient, uploadDone, ctx.Duration(sealTimeoutFlag), sealSignal)
 
+	// the progress of single object is only printed when the objects are uploaded one by one
+	showProgress := concurrency <= 1
+	pool := NewPool(concurrency)
 	for index, object := range taskState.ObjectState {
-		// check object status
-		if object.Status == TaskObjectStatusCreated || object.Status == TaskObjectStatusSeal {
+		// only the objects which have been created on chain need to upload the payload
+		if taskState.GetObjectStatus(index) != TaskObjectStatusCreatedOnChain {
 			continue
 		}
 
-		err := uploadFileByTask(object.BucketName, object.ObjectName, object.FilePath, taskState.Flag, gnfdClient, object.UploadSingleFolder, object.ObjectSize)
-		if err != nil {
-			taskState.UpdateObjectState(index, TaskObjectStatusFailed, err.Error())
-			fmt.Printf("\r%s", fmt.Sprintf("%s %s %s", TaskObjectStatusFailed, object.ObjectName, err.Error()))
-		} else {
-			taskState.UpdateObjectState(index, TaskObjectStatusCreated, "")
-			fmt.Printf("\r%s", fmt.Sprintf("%s %s %s", TaskObjectStatusCreated, object.ObjectName, ""))
-		}
+		pool.Add(1)
+		go func(index int, object *UploadTaskObject) {
+			defer pool.Done()
+			err := uploadFileByTask(object.BucketName, object.ObjectName, object.FilePath, taskState.Flag, gnfdClient, object.IsFolder, object.ObjectSize, showProgress)
+			if err != nil {
+				taskState.UpdateObjectState(index, TaskObjectStatusFailed, err.Error())
+				printTaskObjectState(TaskObjectStatusFailed, object.ObjectName, err.Error())
+			} else {
+				taskState.UpdateObjectState(index, TaskObjectStatusCreated, "")
+				printTaskObjectState(TaskObjectStatusCreated, object.ObjectName, "")
+			}
+		}(index, object)
 	}
+	pool.Wait()
+	close(uploadDone)
 
 	// waiting for seal
 	<-sealSignal
-	cancel()
-	// waiting sync status
-	time.Sleep(5 * time.Second)
-	fmt.Println()
-	if taskState.Status != TaskStatusSuccess {
-		taskState.Status = TaskStatusFail
-	}
-	content, err := json.Marshal(taskState)
-	if err != nil {
+	fmt.Fprintln(infoWriter)
+	if err := taskState.closeJournal(); err != nil {
 		return toCmdErr(err)
 	}
-	taskFileName := fmt.Sprintf("/.%s/state", taskState.TaskID)
-	taskFilePath := filepath.Join(homeDir, taskFileName)
-	if err = createAndWriteFile(taskFilePath, content); err != nil {
-		return toCmdErr(err)
+	if isStructuredOutput() {
+		if err := printRecord(newTaskSummary(taskState)); err != nil {
+			return err
+		}
+	}
+	return getUploadTaskErr(taskState)
+}
+
+// getUploadTaskErr return the error of the finished upload task, the objects still waiting for seal mean the task is timeout
+func getUploadTaskErr(taskState *TaskState) error {
+	var failedNum, unsealedNum int
+	for index := 0; index < len(taskState.ObjectState); index++ {
+		switch taskState.GetObjectStatus(index) {
+		case TaskObjectStatusSeal:
+		case TaskObjectStatusCreated:
+			unsealedNum++
+		default:
+			failedNum++
+		}
+	}
+	if unsealedNum > 0 {
+		return newCmdError(exitCodeTimeout, fmt.Errorf("%d objects have not been sealed, please run \"task retry --taskId %s\" to check them again",
+			unsealedNum, taskState.TaskID))
+	}
+	if failedNum > 0 {
+		return toCmdErr(fmt.Errorf("%d objects failed to be uploaded, please run \"task retry --taskId %s\" to resume the uploading",
+			failedNum, taskState.TaskID))
 	}
 	return nil
 }
 
-// sealChecker Wait for seal and update the status
-func sealChecker(ctx *cli.Context, taskState *TaskState, gnfdClient client.IClient, signal chan int) {
-	tick := time.NewTicker(3 * time.Second)
-	defer tick.Stop()
-	objectOffset := 0
-	for range tick.C {
-		if objectOffset >= len(taskState.ObjectState) {
-			break
+// createObjectsByTask create the objects of the task which have not been created on chain by multi-msg txns,
+// the hash of the txn is recorded in the task state once the object is created
+func createObjectsByTask(gnfdClient client.IClient, taskState *TaskState, batchSize int) {
+	c, cancelCreate := context.WithCancel(globalContext)
+	defer cancelCreate()
+
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	indexes := make([]int, 0, batchSize)
+	msgs := make([][]sdk.Msg, 0, batchSize)
+	// the approvals of the sp expire, so each batch is broadcast as soon as its msgs are built
+	broadcast := func() {
+		if len(msgs) == 0 {
+			return
+		}
+		fmt.Fprintf(infoWriter, "creating %d objects on chain...\n", len(msgs))
+		results := broadcastMsgsInBatch(gnfdClient, c, msgs, batchSize, "CreateObject")
+		for i, result := range results {
+			index := indexes[i]
+			objectName := taskState.ObjectState[index].ObjectName
+			if errors.Is(result.Err, ErrTxnNotConfirmed) {
+				// the txn may have been committed, check the object instead of creating it again
+				created, err := isObjectCreatedOnChain(c, gnfdClient, taskState.ObjectState[index])
+				if err == nil && created {
+					result.Err = nil
+				} else if err != nil {
+					result.Err = fmt.Errorf("%v, %v", result.Err, err)
+				}
+			}
+			if result.Err != nil {
+				taskState.UpdateObjectState(index, TaskObjectStatusFailed, result.Err.Error())
+				printTaskObjectState(TaskObjectStatusFailed, objectName, result.Err.Error())
+				continue
+			}
+			taskState.SetCreateTxnHash(index, result.TxnHash)
+			taskState.UpdateObjectState(index, TaskObjectStatusCreatedOnChain, "")
+		}
+		indexes = indexes[:0]
+		msgs = msgs[:0]
+	}
+
+	for index := 0; index < len(taskState.ObjectState); index++ {
+		status := taskState.GetObjectStatus(index)
+		if status != TaskObjectStatusWaitForUpload && status != TaskObjectStatusFailed {
+			continue
+		}
+		object := taskState.ObjectState[index]
+
+		// if the object exists on chain, no need to create it again, but only upload into it if it has the same content
+		objectDetail, err := gnfdClient.HeadObject(c, object.BucketName, object.ObjectName)
+		if err == nil {
+			status, comment := checkExistingObject(gnfdClient, object, objectDetail.ObjectInfo)
+			taskState.UpdateObjectState(index, status, comment)
+			if status != TaskObjectStatusCreatedOnChain {
+				printTaskObjectState(status, object.ObjectName, comment)
+			}
+			continue
+		}
+		if !isNoSuchObjectErr(err) {
+			err = headObjectErr(object.BucketName, object.ObjectName, err)
+			taskState.UpdateObjectState(index, TaskObjectStatusFailed, err.Error())
+			printTaskObjectState(TaskObjectStatusFailed, object.ObjectName, err.Error())
+			continue
 		}
-		utj := taskState.ObjectState[objectOffset]
-		headObjOutput, queryErr := gnfdClient.HeadObject(ctx.Context, utj.BucketName, utj.ObjectName)
-		if queryErr != nil {
+
+		objectMsgs, err := buildCreateObjectMsgs(c, gnfdClient, object, taskState.Flag)
+		if err != nil {
+			taskState.UpdateObjectState(index, TaskObjectStatusFailed, err.Error())
+			printTaskObjectState(TaskObjectStatusFailed, object.ObjectName, err.Error())
 			continue
 		}
+		indexes = append(indexes, index)
+		msgs = append(msgs, objectMsgs)
+		if len(msgs) >= batchSize {
+			broadcast()
+		}
+	}
+	broadcast()
+}
 
-		if headObjOutput.ObjectInfo.GetObjectStatus().String() == "OBJECT_STATUS_SEALED" {
-			taskState.UpdateObjectState(objectOffset, TaskObjectStatusSeal, "")
-			fmt.Printf("\r%s", fmt.Sprintf("%s %s %s", TaskObjectStatusSeal, utj.ObjectName, ""))
-			objectOffset++
+// checkExistingObject compare the object which already exists on chain with the local file of the task and return
+// the status of the task object: the object of the same content is uploaded or treated as sealed, the object of
+// another content is stale, it may be created by another upload and the payload should not be uploaded into it
+func checkExistingObject(gnfdClient client.IClient, object *UploadTaskObject, objectInfo *storageTypes.ObjectInfo) (string, string) {
+	if object.IsFolder {
+		return TaskObjectStatusCreatedOnChain, ""
+	}
+	hashes, err := computeFileHashes(gnfdClient, object.FilePath)
+	if err != nil {
+		return TaskObjectStatusFailed, fmt.Sprintf("failed to compare the file with the object on chain: %v", err)
+	}
+	if uint64(hashes.size) != objectInfo.PayloadSize || !isChecksumsEqual(hashes.checksums, objectInfo.Checksums) {
+		return TaskObjectStatusStale, "the object already exists on chain with another content, " +
+			"please delete the object or upload the file with another name"
+	}
+	switch objectInfo.GetObjectStatus() {
+	case storageTypes.OBJECT_STATUS_SEALED:
+		return TaskObjectStatusSeal, ""
+	case storageTypes.OBJECT_STATUS_DISCONTINUED:
+		return TaskObjectStatusFailed, "the object has been discontinued"
+	default:
+		return TaskObjectStatusCreatedOnChain, ""
+	}
+}
+
+// buildCreateObjectMsgs build the createObject msg approved by the sp and the setTag msg if the tags are provided
+func buildCreateObjectMsgs(c context.Context, gnfdClient client.IClient, object *UploadTaskObject, uploadFlag UploadFlag) ([]sdk.Msg, error) {
+	var payload io.Reader = strings.NewReader("")
+	if !object.IsFolder {
+		file, err := os.Open(object.FilePath)
+		if err != nil {
+			return nil, err
 		}
+		defer file.Close()
+		payload = file
 	}
 
-	taskState.Status = TaskStatusSuccess
-	signal <- 1
+	checksums, size, redundancyType, err := gnfdClient.ComputeHashRoots(payload, false)
+	if err != nil {
+		return nil, err
+	}
+	return buildCreateObjectMsgsByHashes(c, gnfdClient, object, uploadFlag,
+		&fileHashes{checksums: checksums, size: size, redundancyType: redundancyType})
+}
+
+// buildCreateObjectMsgsByHashes build the msgs of buildCreateObjectMsgs with the integrity hash computed by the caller
+func buildCreateObjectMsgsByHashes(c context.Context, gnfdClient client.IClient, object *UploadTaskObject, uploadFlag UploadFlag,
+	hashes *fileHashes) ([]sdk.Msg, error) {
+	contentType := uploadFlag.ContentType
+	if contentType == "" {
+		// parse the mimeType as content type
+		contentType = sdktypes.ContentDefault
+		if !object.IsFolder {
+			if mimeType, mimeErr := getContentTypeOfFile(object.FilePath); mimeErr == nil {
+				contentType = mimeType
+			}
+		}
+	}
+
+	visibility := uploadFlag.Visibility
+	if visibility == storageTypes.VISIBILITY_TYPE_UNSPECIFIED {
+		visibility = storageTypes.VISIBILITY_TYPE_INHERIT
+	}
+
+	operator := gnfdClient.MustGetDefaultAccount().GetAddress()
+	// use the max uint64 as the timeout height, the same as the sdk
+	createObjectMsg := storageTypes.NewMsgCreateObject(operator, object.BucketName, object.ObjectName,
+		uint64(hashes.size), visibility, hashes.checksums, contentType, hashes.redundancyType, ^uint64(0), nil)
+	if err := createObjectMsg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	signedMsg, err := gnfdClient.GetCreateObjectApproval(c, createObjectMsg)
+	if err != nil {
+		return nil, err
+	}
+	msgs := []sdk.Msg{signedMsg}
+
+	if uploadFlag.Tags != "" {
+		tags := &storageTypes.ResourceTags{}
+		if err = json.Unmarshal([]byte(uploadFlag.Tags), &tags.Tags); err != nil {
+			return nil, err
+		}
+		grn := gtypes.NewObjectGRN(object.BucketName, object.ObjectName)
+		msgs = append(msgs, storageTypes.NewMsgSetTag(operator, grn.String(), tags))
+	}
+	return msgs, nil
 }
 
-// stateSync Periodically synchronize the status to the local file
-func stateSync(ctx context.Context, homeDir string, state *TaskState) {
-	taskFileName := fmt.Sprintf("/.%s/state", state.TaskID)
-	taskFilePath := filepath.Join(homeDir, taskFileName)
-	tick := time.NewTicker(500 * time.Millisecond)
+// the interval of polling the seal status of an object starts from minSealPollInterval and grows until maxSealPollInterval
+const (
+	minSealPollInterval = 2 * time.Second
+	maxSealPollInterval = time.Minute
+	sealPollConcurrency = 16
+)
+
+// sealChecker wait for the uploaded objects to be sealed and update the status. All the outstanding objects
+// are polled concurrently, the polling interval of each object grows if it has not been sealed. The rejected
+// or discontinued objects are marked as failed. It stops when the uploading is done and no object is waiting
+// for seal, or the deadline after the uploading is done is exceeded.
+func sealChecker(ctx *cli.Context, taskState *TaskState, gnfdClient client.IClient, uploadDone <-chan struct{},
+	deadline time.Duration, signal chan int) {
+	var (
+		nextPollTime = make(map[int]time.Time)
+		pollInterval = make(map[int]time.Duration)
+		isUploadDone bool
+		timeout      <-chan time.Time
+		tick         = time.NewTicker(minSealPollInterval)
+	)
 	defer tick.Stop()
+
 	for {
 		select {
+		case <-uploadDone:
+			isUploadDone = true
+			// avoid selecting the closed channel again
+			uploadDone = nil
+			// the deadline starts after all the payloads have been uploaded, no deadline if it is not positive
+			if deadline > 0 {
+				timeout = time.After(deadline)
+			}
+			continue
+		case <-timeout:
+			for index := 0; index < len(taskState.ObjectState); index++ {
+				if taskState.GetObjectStatus(index) == TaskObjectStatusCreated {
+					taskState.UpdateObjectState(index, TaskObjectStatusCreated, fmt.Sprintf("not sealed in %s, retry the task to check it again", deadline))
+				}
+			}
+			fmt.Fprintf(infoWriter, "\nsome objects have not been sealed in %s\n", deadline)
+			taskState.SetStatus(TaskStatusFail)
+			signal <- 1
+			return
 		case <-tick.C:
-			content, err := json.Marshal(state)
-			if err != nil {
+		}
+
+		// the objects whose payload have been uploaded are waiting for seal
+		now := time.Now()
+		waiting := 0
+		due := make([]int, 0)
+		for index := 0; index < len(taskState.ObjectState); index++ {
+			if taskState.GetObjectStatus(index) != TaskObjectStatusCreated {
 				continue
 			}
-			_ = createAndWriteFile(taskFilePath, content)
-		case <-ctx.Done():
-			return
+			waiting++
+			if now.After(nextPollTime[index]) {
+				due = append(due, index)
+			}
+		}
+		if waiting == 0 && isUploadDone {
+			break
+		}
+
+		pool := NewPool(sealPollConcurrency)
+		var lock sync.Mutex
+		for _, index := range due {
+			pool.Add(1)
+			go func(index int) {
+				defer pool.Done()
+				object := taskState.ObjectState[index]
+				sealed := checkObjectSeal(ctx.Context, taskState, gnfdClient, index, object)
+				if sealed {
+					return
+				}
+				lock.Lock()
+				defer lock.Unlock()
+				interval := pollInterval[index] * 3 / 2
+				if interval < minSealPollInterval {
+					interval = minSealPollInterval
+				} else if interval > maxSealPollInterval {
+					interval = maxSealPollInterval
+				}
+				pollInterval[index] = interval
+				nextPollTime[index] = time.Now().Add(interval)
+			}(index)
 		}
+		pool.Wait()
 	}
+
+	taskState.SetStatus(getUploadTaskStatus(taskState))
+	signal <- 1
+}
+
+// checkObjectSeal query the status of the object and update the task state if the object is in a terminal state,
+// it returns false if the object is still waiting for seal
+func checkObjectSeal(c context.Context, taskState *TaskState, gnfdClient client.IClient, index int, object *UploadTaskObject) bool {
+	headObjOutput, queryErr := gnfdClient.HeadObject(c, object.BucketName, object.ObjectName)
+	if queryErr != nil {
+		// the object is deleted if it is rejected by the storage provider
+		if isNoSuchObjectErr(queryErr) {
+			comment := "the object is not found, it may be rejected by the storage provider"
+			taskState.UpdateObjectState(index, TaskObjectStatusFailed, comment)
+			printTaskObjectState(TaskObjectStatusFailed, object.ObjectName, comment)
+			return true
+		}
+		return false
+	}
+
+	switch headObjOutput.ObjectInfo.GetObjectStatus() {
+	case storageTypes.OBJECT_STATUS_SEALED:
+		taskState.UpdateObjectState(index, TaskObjectStatusSeal, "")
+		printTaskObjectState(TaskObjectStatusSeal, object.ObjectName, "")
+		return true
+	case storageTypes.OBJECT_STATUS_DISCONTINUED:
+		comment := "the object has been discontinued"
+		taskState.UpdateObjectState(index, TaskObjectStatusFailed, comment)
+		printTaskObjectState(TaskObjectStatusFailed, object.ObjectName, comment)
+		return true
+	default:
+		return false
+	}
+}
+
+// getUploadTaskStatus return successful if all the objects of the task have been sealed
+func getUploadTaskStatus(taskState *TaskState) string {
+	for index := 0; index < len(taskState.ObjectState); index++ {
+		if taskState.GetObjectStatus(index) != TaskObjectStatusSeal {
+			return TaskStatusFail
+		}
+	}
+	return TaskStatusSuccess
+}
+
+var taskPrintLock sync.Mutex
+
+// printTaskObjectState print the latest state of the object, the printing is serialized among the uploading routines
+func printTaskObjectState(status, objectName, comment string) {
+	taskPrintLock.Lock()
+	defer taskPrintLock.Unlock()
+	fmt.Fprintf(infoWriter, "\r%s", fmt.Sprintf("%s %s %s", status, objectName, comment))
 }
 
 func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Context,
@@ -737,14 +1112,25 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 			}
 		}
 		if printTxnHash {
-			fmt.Printf("object %s created on chain \n", objectName)
-			fmt.Println("transaction hash: ", txnHash)
+			fmt.Fprintf(infoWriter, "object %s created on chain \n", objectName)
+			fmt.Fprintln(infoWriter, "transaction hash: ", txnHash)
 		}
 	} else {
-		fmt.Printf("object %s already exist \n", objectName)
+		fmt.Fprintf(infoWriter, "object %s already exist \n", objectName)
 	}
 
+	// print the record of the uploaded object in json or yaml mode, the txn hash is empty if the object has been
+	// created before, and the callers which print the results by themselves disable printTxnHash
+	printPutRecord := func() error {
+		if !printTxnHash {
+			return nil
+		}
+		return printRecord(txnRecord{Action: "put_object", Resource: bucketName + "/" + objectName, TxnHash: txnHash})
+	}
 	if objectSize == 0 {
+		if isStructuredOutput() {
+			return printPutRecord()
+		}
 		return nil
 	}
 
@@ -769,7 +1155,7 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 	}
 
 	progressReader := &ProgressReader{
-		Reader:      reader,
+		Reader:      newRateLimitedReader(reader),
 		Total:       objectSize,
 		StartTime:   time.Now(),
 		LastPrinted: time.Now(),
@@ -786,7 +1172,7 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 			return toCmdErr(err)
 		}
 	} else {
-		fmt.Printf("resumable uploading %s is beginning...\n", objectName)
+		fmt.Fprintf(infoWriter, "resumable uploading %s is beginning...\n", objectName)
 		if err = gnfdClient.PutObject(c, bucketName, objectName,
 			objectSize, progressReader, opt); err != nil {
 			return toCmdErr(err)
@@ -794,16 +1180,31 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 	}
 
 	if bypassSeal {
-		fmt.Printf("\nupload %s to %s \n", objectName, urlInfo)
+		if isStructuredOutput() {
+			return printPutRecord()
+		}
+		fmt.Fprintf(infoWriter, "\nupload %s to %s \n", objectName, urlInfo)
 		return nil
 	}
 
-	// Check if object is sealed
+	if err = waitObjectSeal(c, gnfdClient, bucketName, objectName); err != nil {
+		return err
+	}
+	if isStructuredOutput() {
+		return printPutRecord()
+	}
+	fmt.Fprintf(infoWriter, "upload %s to %s \n", objectName, urlInfo)
+	return nil
+}
+
+// waitObjectSeal wait for the object to be sealed by the storage providers for at most one hour
+func waitObjectSeal(c context.Context, gnfdClient client.IClient, bucketName, objectName string) error {
 	timeout := time.After(1 * time.Hour)
 	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
 	count := 0
-	fmt.Println()
-	fmt.Println("sealing...")
+	fmt.Fprintln(infoWriter)
+	fmt.Fprintln(infoWriter, "sealing...")
 	for {
 		select {
 		case <-timeout:
@@ -815,11 +1216,9 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 				return queryErr
 			}
 			if count%10 == 0 {
-				fmt.Println("sealing...")
+				fmt.Fprintln(infoWriter, "sealing...")
 			}
 			if headObjOutput.ObjectInfo.GetObjectStatus().String() == "OBJECT_STATUS_SEALED" {
-				ticker.Stop()
-				fmt.Printf("upload %s to %s \n", objectName, urlInfo)
 				return nil
 			}
 		}
@@ -827,7 +1226,7 @@ func uploadFile(bucketName, objectName, filePath, urlInfo string, ctx *cli.Conte
 }
 
 func uploadFileByTask(bucketName, objectName, filePath string, uploadFlag UploadFlag,
-	gnfdClient client.IClient, uploadSingleFolder bool, objectSize int64) error {
+	gnfdClient client.IClient, uploadSingleFolder bool, objectSize int64, showProgress bool) error {
 	var file *os.File
 
 	opts := sdktypes.CreateObjectOptions{}
@@ -911,19 +1310,24 @@ func uploadFileByTask(bucketName, objectName, filePath string, uploadFlag Upload
 		opt.DisableResumable = false
 	}
 
-	progressReader := &ProgressReader{
-		Reader:      reader,
-		Total:       objectSize,
-		StartTime:   time.Now(),
-		LastPrinted: time.Now(),
-	}
+	payloadReader := newRateLimitedReader(reader)
+	if showProgress {
+		progressReader := &ProgressReader{
+			Reader:      payloadReader,
+			Total:       objectSize,
+			StartTime:   time.Now(),
+			LastPrinted: time.Now(),
+		}
 
-	// if print big file progress, the printing progress should be delayed to obtain a more accurate display.
-	if objectSize > progressDelayPrintSize {
-		progressReader.LastPrinted = time.Now().Add(3 * time.Second)
+		// if print big file progress, the printing progress should be delayed to obtain a more accurate display.
+		if objectSize > progressDelayPrintSize {
+			progressReader.LastPrinted = time.Now().Add(3 * time.Second)
+		}
+		payloadReader = progressReader
 	}
+
 	if err = gnfdClient.PutObject(c, bucketName, objectName,
-		objectSize, progressReader, opt); err != nil {
+		objectSize, payloadReader, opt); err != nil {
 		return toCmdErr(err)
 	}
 
@@ -938,13 +1342,24 @@ func getObject(ctx *cli.Context) error {
 	}
 
 	urlInfo := ctx.Args().Get(0)
+	spEndpoint := ctx.String(spEndpointFlag)
+
+	if ctx.Bool(recursiveFlag) {
+		gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: false, ForceToUseSpecifiedSpEndpointForDownloadOnly: spEndpoint})
+		if err != nil {
+			return toCmdErr(err)
+		}
+		if err = downloadFolder(ctx, gnfdClient, urlInfo); err != nil {
+			return toCmdErr(err)
+		}
+		return nil
+	}
+
 	bucketName, objectName, err := ParseBucketAndObject(urlInfo)
 	if err != nil {
 		return toCmdErr(err)
 	}
 
-	spEndpoint := ctx.String(spEndpointFlag)
-
 	gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: false, ForceToUseSpecifiedSpEndpointForDownloadOnly: spEndpoint})
 	if err != nil {
 		return toCmdErr(err)
@@ -953,6 +1368,18 @@ func getObject(ctx *cli.Context) error {
 	c, cancelGetObject := context.WithCancel(globalContext)
 	defer cancelGetObject()
 
+	verify := ctx.Bool(verifyFlag)
+	if verify && (ctx.Int64(startOffsetFlag) != 0 || ctx.Int64(endOffsetFlag) != 0) {
+		return toCmdErr(errors.New("the verify flag can not be used with the range of the download body"))
+	}
+
+	if ctx.Args().Get(1) == stdioFileArg {
+		if ctx.Bool(resumableFlag) || verify {
+			return toCmdErr(errors.New("resumable download and verify are not supported when writing to stdout"))
+		}
+		return downloadObjectToStdout(ctx, c, gnfdClient, bucketName, objectName)
+	}
+
 	var filePath string
 	if ctx.Args().Len() == 1 {
 		filePath = objectName
@@ -988,57 +1415,405 @@ func getObject(ctx *cli.Context) error {
 		}
 	}
 
+	// download the ranges of the object in parallel, the download can be resumed by the checkpoint.
+	// The resumable download of the sdk writes the file by itself and can not be limited, so if the rate is limited,
+	// the resumable download is done part by part with the checkpoint of the parallel download instead.
+	concurrency := ctx.Int(concurrencyFlag)
+	if limitResumable := resumableDownload && globalRateLimiter != nil; concurrency > 1 || limitResumable {
+		if startOffset != 0 || endOffset != 0 {
+			return toCmdErr(errors.New("the concurrency flag and the resumable download with the limited rate can not be used with the range of the download body"))
+		}
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		if err = downloadObjectInParallel(c, gnfdClient, bucketName, objectName, filePath, concurrency, int64(partSize), verify); err != nil {
+			return toCmdErr(err)
+		}
+		return nil
+	}
+
 	if resumableDownload {
 		opt.PartSize = partSize
 		err = gnfdClient.FGetObjectResumable(c, bucketName, objectName, filePath, opt)
 		if err != nil {
 			return toCmdErr(err)
 		}
-		fmt.Printf("resumable download object %s, the file path is %s \n", objectName, filePath)
+		if verify {
+			if err = verifyFileWithObject(c, gnfdClient, bucketName, objectName, filePath); err != nil {
+				os.Remove(filePath)
+				return toCmdErr(err)
+			}
+		}
+		if isStructuredOutput() {
+			return printRecord(downloadRecord{BucketName: bucketName, ObjectName: objectName, FilePath: filePath})
+		}
+		fmt.Fprintf(infoWriter, "resumable download object %s, the file path is %s \n", objectName, filePath)
 	} else {
-		var fd *os.File
-		dir := filepath.Dir(filePath)
-		fileName := "." + filepath.Base(filePath) + ".tmp"
-		tempFilePath := filepath.Join(dir, fileName)
+		size, err := downloadObjectByTempFile(c, gnfdClient, bucketName, objectName, filePath, opt, verify)
+		if err != nil {
+			return toCmdErr(err)
+		}
+		if isStructuredOutput() {
+			return printRecord(downloadRecord{BucketName: bucketName, ObjectName: objectName, FilePath: filePath, Size: size})
+		}
+		fmt.Fprintf(infoWriter, "\ndownload object %s, the file path is %s, content length:%d \n", objectName, filePath, uint64(size))
+	}
 
-		tempFilePath, err = checkIfDownloadFileExist(tempFilePath, objectName)
+	return nil
+}
+
+// downloadObjectByTempFile download the object (or its range) to a temp file and rename it to the file path, the
+// size of the downloaded body is returned. The temp file is removed on failure so that the download can be retried.
+func downloadObjectByTempFile(c context.Context, gnfdClient client.IClient, bucketName, objectName, filePath string,
+	opt sdktypes.GetObjectOptions, verify bool) (size int64, err error) {
+	tempFilePath := filepath.Join(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	tempFilePath, err = checkIfDownloadFileExist(tempFilePath, objectName)
+	if err != nil {
+		return 0, err
+	}
+	// download to the temp file firstly
+	fd, err := os.OpenFile(tempFilePath, os.O_CREATE|os.O_WRONLY, 0660)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
 		if err != nil {
+			os.Remove(tempFilePath)
+		}
+	}()
+	defer fd.Close()
+
+	body, info, err := gnfdClient.GetObject(c, bucketName, objectName, opt)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	pw := &ProgressWriter{
+		Writer:      newRateLimitedWriter(fd),
+		Total:       info.Size,
+		StartTime:   time.Now(),
+		LastPrinted: time.Now(),
+	}
+	if size, err = io.Copy(pw, body); err != nil {
+		return 0, err
+	}
+	if err = fd.Close(); err != nil {
+		return 0, err
+	}
+
+	if verify {
+		if err = verifyFileWithObject(c, gnfdClient, bucketName, objectName, tempFilePath); err != nil {
+			return 0, err
+		}
+	}
+	if err = os.Rename(tempFilePath, filePath); err != nil {
+		return 0, fmt.Errorf("failed to rename %s to %s: %v", tempFilePath, filePath, err)
+	}
+	return size, nil
+}
+
+// downloadObjectToStdout write the object payload to stdout, no message is printed to stdout
+// so that the payload can be piped to other commands
+func downloadObjectToStdout(ctx *cli.Context, c context.Context, gnfdClient client.IClient, bucketName, objectName string) error {
+	opt := sdktypes.GetObjectOptions{}
+	startOffset := ctx.Int64(startOffsetFlag)
+	endOffset := ctx.Int64(endOffsetFlag)
+	if startOffset != 0 || endOffset != 0 {
+		if err := opt.SetRange(startOffset, endOffset); err != nil {
 			return toCmdErr(err)
 		}
-		// download to the temp file firstly
-		fd, err = os.OpenFile(tempFilePath, os.O_CREATE|os.O_WRONLY, 0660)
+	}
+
+	body, _, err := gnfdClient.GetObject(c, bucketName, objectName, opt)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	defer body.Close()
+
+	if _, err = io.Copy(newRateLimitedWriter(resultWriter), body); err != nil {
+		return toCmdErr(err)
+	}
+	return nil
+}
+
+// downloadFolder download the objects under the prefix to the local directory in a recursive way,
+// the local directory tree is rebuilt by the object names. The download is run as a task, so it can be
+// checked and resumed by the task commands.
+func downloadFolder(ctx *cli.Context, gnfdClient client.IClient, urlInfo string) error {
+	bucketName, prefixName, err := ParseBucketAndPrefix(urlInfo)
+	if err != nil {
+		return err
+	}
+	if prefixName != "" && !strings.HasSuffix(prefixName, "/") {
+		prefixName = prefixName + "/"
+	}
+
+	localDir := "."
+	if ctx.Args().Len() > 1 {
+		localDir = ctx.Args().Get(1)
+	}
+	// the paths of the task are absolute, so that the task can be retried in another working directory
+	if localDir, err = filepath.Abs(localDir); err != nil {
+		return err
+	}
+	if err = os.MkdirAll(localDir, 0755); err != nil {
+		return fmt.Errorf("failed to create local directory %s: %v", localDir, err)
+	}
+
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return err
+	}
+
+	c, cancelDownload := context.WithCancel(globalContext)
+	defer cancelDownload()
+
+	if _, err = gnfdClient.HeadBucket(c, bucketName); err != nil {
+		return headBucketErr(bucketName, err)
+	}
+
+	taskID := uuid.New().String()
+	taskState := &TaskState{
+		Lock:        new(sync.Mutex),
+		ObjectState: make(map[int]*UploadTaskObject),
+		TaskID:      taskID,
+		FolderName:  localDir,
+		BucketName:  bucketName,
+		Status:      TaskStatusCreate,
+		CreateTime:  time.Now().Unix(),
+		Type:        TaskTypeDownload,
+		Prefix:      prefixName,
+		Verify:      ctx.Bool(verifyFlag),
+	}
+
+	var (
+		listResult        sdktypes.ListObjectsResult
+		continuationToken string
+		objectIndex       int
+	)
+	for {
+		listResult, err = gnfdClient.ListObjects(c, bucketName, sdktypes.ListObjectsOptions{ShowRemovedObject: false,
+			MaxKeys:           defaultMaxKey,
+			ContinuationToken: continuationToken,
+			Prefix:            prefixName})
 		if err != nil {
 			return err
 		}
 
-		defer fd.Close()
+		for _, object := range listResult.Objects {
+			info := object.ObjectInfo
+			filePath, pathErr := getDownloadPathOfObject(localDir, prefixName, info.ObjectName)
+			if pathErr != nil {
+				fmt.Fprintf(infoWriter, "skip object %s: %v\n", info.ObjectName, pathErr)
+				continue
+			}
+
+			isFolder := strings.HasSuffix(info.ObjectName, "/")
+			if !isFolder && info.GetObjectStatus() != storageTypes.OBJECT_STATUS_SEALED {
+				fmt.Fprintf(infoWriter, "skip object %s which has not been sealed\n", info.ObjectName)
+				continue
+			}
 
-		body, info, downloadErr := gnfdClient.GetObject(c, bucketName, objectName, opt)
-		if downloadErr != nil {
-			return toCmdErr(downloadErr)
+			taskState.ObjectState[objectIndex] = &UploadTaskObject{
+				BucketName: bucketName,
+				ObjectName: info.ObjectName,
+				FilePath:   filePath,
+				IsFolder:   isFolder,
+				ObjectSize: int64(info.PayloadSize),
+				Status:     TaskObjectStatusWaitForDownload,
+			}
+			objectIndex++
 		}
 
-		pw := &ProgressWriter{
-			Writer:      fd,
-			Total:       info.Size,
-			StartTime:   time.Now(),
-			LastPrinted: time.Now(),
+		if !listResult.IsTruncated {
+			break
+		}
+		continuationToken = listResult.NextContinuationToken
+	}
+
+	fmt.Fprintln(infoWriter, "================================================")
+	fmt.Fprintln(infoWriter, "Your batch download is submitted as a task, task ID is "+taskID)
+	fmt.Fprintln(infoWriter, "You can check your task status and progress by using cmd as below:\n\n- List all your tasks: ./gnfd-cmd task ls\n- Check status: ./gnfd-cmd task status --taskId taskID\n- Retry (in case this process is killed accidentally): ./gnfd-cmd task retry --taskId taskID\n- Delete task: ./gnfd-cmd task delete --taskId taskID\n\n>>================================================")
+
+	return downloadFolderByTask(homeDir, gnfdClient, taskState, ctx.Int(concurrencyFlag))
+}
+
+// downloadFolderByTask download the objects of the task which have not been downloaded,
+// at most concurrency objects are downloaded at the same time
+func downloadFolderByTask(homeDir string, gnfdClient client.IClient, taskState *TaskState, concurrency int) error {
+	if err := taskState.openJournal(homeDir); err != nil {
+		return err
+	}
+
+	c, cancelDownload := context.WithCancel(globalContext)
+	defer cancelDownload()
+
+	// the progress of single object is only printed when the objects are downloaded one by one
+	showProgress := concurrency <= 1
+	pool := NewPool(concurrency)
+
+	var (
+		failedNum int64
+		lock      sync.Mutex
+	)
+	for index := 0; index < len(taskState.ObjectState); index++ {
+		if taskState.GetObjectStatus(index) == TaskObjectStatusDownloaded {
+			continue
+		}
+		object := taskState.ObjectState[index]
+
+		// the object with "/" suffix is a folder, just create the directory
+		if object.IsFolder {
+			if err := os.MkdirAll(object.FilePath, 0755); err != nil {
+				lock.Lock()
+				failedNum++
+				taskState.UpdateObjectState(index, TaskObjectStatusFailed, err.Error())
+				lock.Unlock()
+				continue
+			}
+			taskState.UpdateObjectState(index, TaskObjectStatusDownloaded, "")
+			continue
+		}
+
+		pool.Add(1)
+		go func(index int, object *UploadTaskObject) {
+			defer pool.Done()
+			// the file which is not written by the task is kept only if it is the same as the object
+			downloaded, checkErr := checkExistingDownloadFile(c, gnfdClient, object)
+			if checkErr != nil || downloaded {
+				lock.Lock()
+				defer lock.Unlock()
+				if checkErr != nil {
+					failedNum++
+					taskState.UpdateObjectState(index, TaskObjectStatusFailed, checkErr.Error())
+					fmt.Fprintf(infoWriter, "\nfailed to download object %s: %v\n", object.ObjectName, checkErr)
+					return
+				}
+				fmt.Fprintf(infoWriter, "skip object %s which has been downloaded to %s\n", object.ObjectName, object.FilePath)
+				taskState.UpdateObjectState(index, TaskObjectStatusDownloaded, "")
+				return
+			}
+			downloadErr := downloadObjectToPath(c, gnfdClient, object.BucketName, object.ObjectName, object.FilePath, object.ObjectSize, showProgress, taskState.Verify)
+			lock.Lock()
+			defer lock.Unlock()
+			if downloadErr != nil {
+				failedNum++
+				taskState.UpdateObjectState(index, TaskObjectStatusFailed, downloadErr.Error())
+				fmt.Fprintf(infoWriter, "\nfailed to download object %s: %v\n", object.ObjectName, downloadErr)
+				return
+			}
+			taskState.UpdateObjectState(index, TaskObjectStatusDownloaded, "")
+			fmt.Fprintf(infoWriter, "\ndownload object %s, the file path is %s, content length:%d \n", object.ObjectName, object.FilePath, object.ObjectSize)
+		}(index, object)
+	}
+	pool.Wait()
+
+	if failedNum > 0 {
+		taskState.SetStatus(TaskStatusFail)
+	} else {
+		taskState.SetStatus(TaskStatusSuccess)
+	}
+	if err := taskState.closeJournal(); err != nil {
+		return err
+	}
+	if isStructuredOutput() {
+		if err := printRecord(newTaskSummary(taskState)); err != nil {
+			return err
 		}
+	}
+
+	if failedNum > 0 {
+		return fmt.Errorf("%d objects failed to be downloaded, please run \"task retry --taskId %s\" to resume the downloading", failedNum, taskState.TaskID)
+	}
+	return nil
+}
+
+// checkExistingDownloadFile check the local file of the object which has not been recorded as downloaded by the task.
+// It returns true if the file matches the checksums of the object, e.g. it has been downloaded by the task before the
+// task state was saved. An error is returned if the file exists with another content, so it is never overwritten or
+// reported as downloaded.
+func checkExistingDownloadFile(c context.Context, gnfdClient client.IClient, object *UploadTaskObject) (bool, error) {
+	stat, err := os.Stat(object.FilePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if stat.IsDir() || stat.Size() != object.ObjectSize {
+		return false, fmt.Errorf("download file:%s already exist", object.FilePath)
+	}
+	if err = verifyFileWithObject(c, gnfdClient, object.BucketName, object.ObjectName, object.FilePath); err != nil {
+		return false, fmt.Errorf("download file:%s already exist and does not match the object: %v", object.FilePath, err)
+	}
+	return true, nil
+}
+
+// getDownloadPathOfObject return the local path of the object, the object name is relative to the prefix
+func getDownloadPathOfObject(localDir, prefixName, objectName string) (string, error) {
+	relativeName := strings.TrimPrefix(objectName, prefixName)
+	filePath := filepath.Join(localDir, filepath.FromSlash(relativeName))
+	// refuse the object names like "../xx" which point to the path outside the local directory
+	relPath, err := filepath.Rel(localDir, filePath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("the object name %s is out of the local directory", objectName)
+	}
+	return filePath, nil
+}
 
-		_, err = io.Copy(pw, body)
+// downloadObjectToPath download the object to a temp file firstly and rename it to the file path after finishing,
+// if verify is set, the temp file is verified with the checksums of the object before renaming.
+// The temp file is removed if the download fails.
+func downloadObjectToPath(c context.Context, gnfdClient client.IClient, bucketName, objectName, filePath string,
+	objectSize int64, showProgress, verify bool) (err error) {
+	if err = os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	tempFilePath := filepath.Join(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	fd, err := os.OpenFile(tempFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0660)
+	if err != nil {
+		return err
+	}
+	defer func() {
 		if err != nil {
-			return toCmdErr(err)
+			os.Remove(tempFilePath)
 		}
+	}()
+	defer fd.Close()
 
-		err = os.Rename(tempFilePath, filePath)
+	// no need to send request to SP for the empty object
+	if objectSize > 0 {
+		body, _, err := gnfdClient.GetObject(c, bucketName, objectName, sdktypes.GetObjectOptions{})
 		if err != nil {
-			fmt.Printf("failed to rename %s to %s \n", tempFilePath, filePath)
-			return nil
+			return err
+		}
+		defer body.Close()
+
+		writer := newRateLimitedWriter(fd)
+		if showProgress {
+			writer = &ProgressWriter{
+				Writer:      writer,
+				Total:       objectSize,
+				StartTime:   time.Now(),
+				LastPrinted: time.Now(),
+			}
+		}
+		if _, err = io.Copy(writer, body); err != nil {
+			return err
 		}
-		fmt.Printf("\ndownload object %s, the file path is %s, content length:%d \n", objectName, filePath, uint64(info.Size))
 	}
 
-	return nil
+	if err = fd.Close(); err != nil {
+		return err
+	}
+
+	if verify {
+		if err = verifyFileWithObject(c, gnfdClient, bucketName, objectName, tempFilePath); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tempFilePath, filePath)
 }
 
 // cancelCreateObject cancel the created object on chain
@@ -1066,12 +1841,16 @@ func cancelCreateObject(ctx *cli.Context) error {
 		return toCmdErr(ErrObjectNotCreated)
 	}
 
-	_, err = cli.CancelCreateObject(c, bucketName, objectName, sdktypes.CancelCreateOption{TxOpts: &TxnOptionWithSyncMode})
+	txnHash, err := cli.CancelCreateObject(c, bucketName, objectName, sdktypes.CancelCreateOption{TxOpts: &TxnOptionWithSyncMode})
 	if err != nil {
 		return toCmdErr(err)
 	}
 
-	fmt.Println("cancel create object:", objectName)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "cancel_create_object", Resource: bucketName + "/" + objectName, TxnHash: txnHash})
+	}
+
+	fmt.Fprintln(infoWriter, "cancel create object:", objectName)
 	return nil
 }
 
@@ -1094,7 +1873,7 @@ func listObjects(ctx *cli.Context) error {
 
 	_, err = client.HeadBucket(c, bucketName)
 	if err != nil {
-		return toCmdErr(ErrBucketNotExist)
+		return toCmdErr(headBucketErr(bucketName, err))
 	}
 
 	supportRecursive := ctx.Bool(recursiveFlag)
@@ -1130,7 +1909,9 @@ func listObjectByPage(cli client.IClient, c context.Context, bucketName, prefixN
 			return toCmdErr(err)
 		}
 
-		printListResult(listResult)
+		if err = printListResult(bucketName, listResult); err != nil {
+			return err
+		}
 		if !listResult.IsTruncated {
 			break
 		}
@@ -1140,19 +1921,55 @@ func listObjectByPage(cli client.IClient, c context.Context, bucketName, prefixN
 	return nil
 }
 
-func printListResult(listResult sdktypes.ListObjectsResult) {
+// objectListRecord is the record of the objects and the folders listed by "object ls"
+type objectListRecord struct {
+	Type        string `json:"type"`
+	BucketName  string `json:"bucket_name"`
+	ObjectName  string `json:"object_name"`
+	ObjectID    string `json:"object_id,omitempty"`
+	Size        uint64 `json:"size"`
+	ContentType string `json:"content_type,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
+	Status      string `json:"status,omitempty"`
+	CreateTime  string `json:"create_time,omitempty"`
+}
+
+func printListResult(bucketName string, listResult sdktypes.ListObjectsResult) error {
+	if isStructuredOutput() {
+		for _, object := range listResult.Objects {
+			info := object.ObjectInfo
+			if err := printRecord(objectListRecord{
+				Type:        "object",
+				BucketName:  info.BucketName,
+				ObjectName:  info.ObjectName,
+				ObjectID:    info.Id.String(),
+				Size:        info.PayloadSize,
+				ContentType: info.ContentType,
+				Visibility:  info.Visibility.String(),
+				Status:      info.ObjectStatus.String(),
+				CreateTime:  formatRecordTime(info.CreateAt),
+			}); err != nil {
+				return err
+			}
+		}
+		for _, prefix := range listResult.CommonPrefixes {
+			if err := printRecord(objectListRecord{Type: "prefix", BucketName: bucketName, ObjectName: prefix}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	timeWidth := timeColumnWidth()
 	for _, object := range listResult.Objects {
 		info := object.ObjectInfo
-		location, _ := time.LoadLocation("Asia/Shanghai")
-		t := time.Unix(info.CreateAt, 0).In(location)
-
-		fmt.Printf("%s %15d %s \n", t.Format(iso8601DateFormat), info.PayloadSize, info.ObjectName)
+		fmt.Fprintf(infoWriter, "%-*s %15d %s \n", timeWidth, formatTime(info.CreateAt), info.PayloadSize, info.ObjectName)
 	}
 	// list the folders
 	for _, prefix := range listResult.CommonPrefixes {
-		fmt.Printf("%s %15s %s \n", strings.Repeat(" ", len(iso8601DateFormat)), "PRE", prefix)
+		fmt.Fprintf(infoWriter, "%s %15s %s \n", strings.Repeat(" ", timeWidth), "PRE", prefix)
 	}
-
+	return nil
 }
 
 func updateObject(ctx *cli.Context) error {
@@ -1186,8 +2003,7 @@ func updateObject(ctx *cli.Context) error {
 
 	txnHash, err := client.UpdateObjectVisibility(c, bucketName, objectName, visibilityType, sdktypes.UpdateObjectOption{TxOpts: &TxnOptionWithSyncMode})
 	if err != nil {
-		fmt.Println("update object visibility error:", err.Error())
-		return nil
+		return toCmdErr(err)
 	}
 
 	err = waitTxnStatus(client, c, txnHash, "UpdateObject")
@@ -1195,17 +2011,28 @@ func updateObject(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "update_object", Resource: bucketName + "/" + objectName, TxnHash: txnHash})
+	}
+
 	objectDetail, err := client.HeadObject(c, bucketName, objectName)
 	if err != nil {
 		// head fail, no need to print the error
 		return nil
 	}
 
-	fmt.Printf("update object visibility finished, latest object visibility:%s\n", objectDetail.ObjectInfo.GetVisibility().String())
-	fmt.Println("transaction hash: ", txnHash)
+	fmt.Fprintf(infoWriter, "update object visibility finished, latest object visibility:%s\n", objectDetail.ObjectInfo.GetVisibility().String())
+	fmt.Fprintln(infoWriter, "transaction hash: ", txnHash)
 	return nil
 }
 
+// uploadProgressRecord is the record of the uploading progress queried by "object get-progress"
+type uploadProgressRecord struct {
+	BucketName string `json:"bucket_name"`
+	ObjectName string `json:"object_name"`
+	Progress   string `json:"progress"`
+}
+
 func getUploadInfo(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
 		return toCmdErr(fmt.Errorf("args number should be 1"))
@@ -1230,7 +2057,10 @@ func getUploadInfo(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Println("uploading progress:", uploadInfo)
+	if isStructuredOutput() {
+		return printRecord(uploadProgressRecord{BucketName: bucketName, ObjectName: objectName, Progress: uploadInfo})
+	}
+	fmt.Fprintln(infoWriter, "uploading progress:", uploadInfo)
 	return nil
 }
 
@@ -1277,6 +2107,9 @@ func mirrorObject(ctx *cli.Context) error {
 	if err != nil {
 		return toCmdErr(err)
 	}
-	fmt.Printf("mirror object succ, txHash: %s\n", txResp.TxHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "mirror_object", Resource: bucketName + "/" + objectName, ResourceID: id.String(), TxnHash: txResp.TxHash})
+	}
+	fmt.Fprintf(infoWriter, "mirror object succ, txHash: %s\n", txResp.TxHash)
 	return nil
 }
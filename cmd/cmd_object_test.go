@@ -0,0 +1,380 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	storageTypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func TestGetDownloadPathOfObject(t *testing.T) {
+	localDir := filepath.Join("download", "dir")
+
+	tests := []struct {
+		name       string
+		prefixName string
+		objectName string
+		want       string
+		wantErr    bool
+	}{
+		{name: "bucket", objectName: "a/b.txt", want: filepath.Join(localDir, "a", "b.txt")},
+		{name: "prefix", prefixName: "a/", objectName: "a/b/c.txt", want: filepath.Join(localDir, "b", "c.txt")},
+		{name: "dot dot in name", objectName: "a/..b.txt", want: filepath.Join(localDir, "a", "..b.txt")},
+		{name: "parent inside", objectName: "a/../b.txt", want: filepath.Join(localDir, "b.txt")},
+		{name: "absolute name", objectName: "/etc/passwd", want: filepath.Join(localDir, "etc", "passwd")},
+		{name: "parent", objectName: "../b.txt", wantErr: true},
+		{name: "parent after prefix", prefixName: "a/", objectName: "a/../../b.txt", wantErr: true},
+		{name: "parent dir", objectName: "a/../..", wantErr: true},
+		{name: "grandparent", objectName: "../../b.txt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getDownloadPathOfObject(localDir, tt.prefixName, tt.objectName)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// newFakeHashClient return a client whose integrity hash of a payload is the payload itself
+func newFakeHashClient() *fakeGnfdClient {
+	return &fakeGnfdClient{
+		computeHashRoots: func(reader io.Reader) ([][]byte, int64, storageTypes.RedundancyType, error) {
+			content, err := io.ReadAll(reader)
+			return [][]byte{content}, int64(len(content)), storageTypes.REDUNDANCY_EC_TYPE, err
+		},
+	}
+}
+
+func TestCheckExistingObject(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(filePath, []byte("content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sameContent := [][]byte{[]byte("content")}
+
+	tests := []struct {
+		name       string
+		object     *UploadTaskObject
+		objectInfo *storageTypes.ObjectInfo
+		wantStatus string
+	}{
+		{
+			name:       "folder",
+			object:     &UploadTaskObject{ObjectName: "dir/", IsFolder: true},
+			objectInfo: &storageTypes.ObjectInfo{ObjectStatus: storageTypes.OBJECT_STATUS_SEALED},
+			wantStatus: TaskObjectStatusCreatedOnChain,
+		},
+		{
+			name:       "same content not sealed",
+			object:     &UploadTaskObject{ObjectName: "file", FilePath: filePath},
+			objectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: sameContent, ObjectStatus: storageTypes.OBJECT_STATUS_CREATED},
+			wantStatus: TaskObjectStatusCreatedOnChain,
+		},
+		{
+			name:       "same content sealed",
+			object:     &UploadTaskObject{ObjectName: "file", FilePath: filePath},
+			objectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: sameContent, ObjectStatus: storageTypes.OBJECT_STATUS_SEALED},
+			wantStatus: TaskObjectStatusSeal,
+		},
+		{
+			name:       "same content discontinued",
+			object:     &UploadTaskObject{ObjectName: "file", FilePath: filePath},
+			objectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: sameContent, ObjectStatus: storageTypes.OBJECT_STATUS_DISCONTINUED},
+			wantStatus: TaskObjectStatusFailed,
+		},
+		{
+			name:   "another content of the same size",
+			object: &UploadTaskObject{ObjectName: "file", FilePath: filePath},
+			objectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: [][]byte{[]byte("changed")},
+				ObjectStatus: storageTypes.OBJECT_STATUS_CREATED},
+			wantStatus: TaskObjectStatusStale,
+		},
+		{
+			name:       "another size",
+			object:     &UploadTaskObject{ObjectName: "file", FilePath: filePath},
+			objectInfo: &storageTypes.ObjectInfo{PayloadSize: 8, Checksums: sameContent, ObjectStatus: storageTypes.OBJECT_STATUS_SEALED},
+			wantStatus: TaskObjectStatusStale,
+		},
+		{
+			name:       "missing file",
+			object:     &UploadTaskObject{ObjectName: "file", FilePath: filePath + ".missing"},
+			objectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: sameContent, ObjectStatus: storageTypes.OBJECT_STATUS_SEALED},
+			wantStatus: TaskObjectStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, comment := checkExistingObject(newFakeHashClient(), tt.object, tt.objectInfo)
+			if status != tt.wantStatus {
+				t.Errorf("got the status %s (%s), expected %s", status, comment, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCheckObjectSeal(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	tests := []struct {
+		name       string
+		status     storageTypes.ObjectStatus
+		headErr    error
+		wantDone   bool
+		wantStatus string
+	}{
+		{name: "sealed", status: storageTypes.OBJECT_STATUS_SEALED, wantDone: true, wantStatus: TaskObjectStatusSeal},
+		{name: "waiting for seal", status: storageTypes.OBJECT_STATUS_CREATED, wantStatus: TaskObjectStatusCreated},
+		{name: "discontinued", status: storageTypes.OBJECT_STATUS_DISCONTINUED, wantDone: true, wantStatus: TaskObjectStatusFailed},
+		{name: "rejected", headErr: storageTypes.ErrNoSuchObject, wantDone: true, wantStatus: TaskObjectStatusFailed},
+		{name: "query failed", headErr: errors.New("connection refused"), wantStatus: TaskObjectStatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object := &UploadTaskObject{BucketName: "bucket", ObjectName: "file", Status: TaskObjectStatusCreated}
+			taskState := &TaskState{Lock: new(sync.Mutex), ObjectState: map[int]*UploadTaskObject{0: object}}
+			gnfdClient := &fakeGnfdClient{
+				headObject: func(bucketName, objectName string) (*sdktypes.ObjectDetail, error) {
+					if tt.headErr != nil {
+						return nil, tt.headErr
+					}
+					return &sdktypes.ObjectDetail{ObjectInfo: &storageTypes.ObjectInfo{ObjectStatus: tt.status}}, nil
+				},
+			}
+
+			if done := checkObjectSeal(globalContext, taskState, gnfdClient, 0, object); done != tt.wantDone {
+				t.Errorf("got done %v, expected %v", done, tt.wantDone)
+			}
+			if status := taskState.GetObjectStatus(0); status != tt.wantStatus {
+				t.Errorf("got the status %s, expected %s", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetUploadTaskStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		want     string
+	}{
+		{name: "all sealed", statuses: []string{TaskObjectStatusSeal, TaskObjectStatusSeal}, want: TaskStatusSuccess},
+		{name: "not sealed", statuses: []string{TaskObjectStatusSeal, TaskObjectStatusCreated}, want: TaskStatusFail},
+		{name: "failed", statuses: []string{TaskObjectStatusFailed, TaskObjectStatusSeal}, want: TaskStatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskState := &TaskState{Lock: new(sync.Mutex), ObjectState: make(map[int]*UploadTaskObject)}
+			for index, status := range tt.statuses {
+				taskState.ObjectState[index] = &UploadTaskObject{Status: status}
+			}
+			if got := getUploadTaskStatus(taskState); got != tt.want {
+				t.Errorf("got %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckExistingDownloadFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"same": "content", "changed": "Content", "longer": "content!"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		fileName       string
+		wantDownloaded bool
+		wantErr        bool
+	}{
+		{name: "missing file", fileName: "missing"},
+		{name: "same content", fileName: "same", wantDownloaded: true},
+		{name: "another content of the same size", fileName: "changed", wantErr: true},
+		{name: "another size", fileName: "longer", wantErr: true},
+		{name: "directory", fileName: "dir", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gnfdClient := newFakeHashClient()
+			gnfdClient.headObject = func(bucketName, objectName string) (*sdktypes.ObjectDetail, error) {
+				return &sdktypes.ObjectDetail{ObjectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: [][]byte{[]byte("content")}}}, nil
+			}
+			object := &UploadTaskObject{BucketName: "bucket", ObjectName: "object", FilePath: filepath.Join(dir, tt.fileName), ObjectSize: 7}
+
+			downloaded, err := checkExistingDownloadFile(globalContext, gnfdClient, object)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got the error %v, expected an error %v", err, tt.wantErr)
+			}
+			if downloaded != tt.wantDownloaded {
+				t.Errorf("got downloaded %v, expected %v", downloaded, tt.wantDownloaded)
+			}
+		})
+	}
+}
+
+// errReader return the content and then fail, like the body of a broken connection
+type errReader struct {
+	content io.Reader
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	n, err := r.content.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset by peer")
+	}
+	return n, err
+}
+
+func TestDownloadObjectByTempFile(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	tests := []struct {
+		name      string
+		getObject func(bucketName, objectName string, opts sdktypes.GetObjectOptions) (io.ReadCloser, error)
+		payload   string
+		verify    bool
+		wantErr   bool
+	}{
+		{
+			name: "download",
+			getObject: func(bucketName, objectName string, opts sdktypes.GetObjectOptions) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("content")), nil
+			},
+			verify: true,
+		},
+		{
+			name: "request failed",
+			getObject: func(bucketName, objectName string, opts sdktypes.GetObjectOptions) (io.ReadCloser, error) {
+				return nil, errors.New("connection refused")
+			},
+			wantErr: true,
+		},
+		{
+			name: "copy failed",
+			getObject: func(bucketName, objectName string, opts sdktypes.GetObjectOptions) (io.ReadCloser, error) {
+				return io.NopCloser(&errReader{content: strings.NewReader("cont")}), nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "verify failed",
+			getObject: func(bucketName, objectName string, opts sdktypes.GetObjectOptions) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("Content")), nil
+			},
+			verify:  true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "object")
+			tempFilePath := filepath.Join(filepath.Dir(filePath), ".object.tmp")
+			gnfdClient := newFakeHashClient()
+			gnfdClient.getObject = tt.getObject
+			gnfdClient.headObject = func(bucketName, objectName string) (*sdktypes.ObjectDetail, error) {
+				return &sdktypes.ObjectDetail{ObjectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: [][]byte{[]byte("content")}}}, nil
+			}
+
+			// the temp file must be removed on failure, or the retry fails as the temp file already exists
+			for attempt := 0; attempt < 2; attempt++ {
+				size, err := downloadObjectByTempFile(globalContext, gnfdClient, "bucket", "object", filePath, sdktypes.GetObjectOptions{}, tt.verify)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("got the error %v of the attempt %d, expected an error %v", err, attempt, tt.wantErr)
+				}
+				if _, statErr := os.Stat(tempFilePath); !os.IsNotExist(statErr) {
+					t.Fatalf("the temp file is left after the attempt %d: %v", attempt, statErr)
+				}
+				if tt.wantErr {
+					if _, statErr := os.Stat(filePath); !os.IsNotExist(statErr) {
+						t.Errorf("got the file after the failed attempt %d: %v", attempt, statErr)
+					}
+					continue
+				}
+				content, readErr := os.ReadFile(filePath)
+				if readErr != nil || string(content) != "content" || size != int64(len(content)) {
+					t.Errorf("got the file %q (%v) of the size %d, expected %q", content, readErr, size, "content")
+				}
+				break
+			}
+		})
+	}
+}
+
+// TestDownloadFolderByTaskKeepsLocalFiles download the objects into a directory with a stale file, the stale file is
+// neither overwritten nor reported as downloaded, and the objects downloaded by the task are not downloaded again
+func TestDownloadFolderByTaskKeepsLocalFiles(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	homeDir, localDir := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(localDir, "stale"), []byte("Content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	taskState := &TaskState{Lock: new(sync.Mutex), TaskID: "task", Type: TaskTypeDownload, ObjectState: map[int]*UploadTaskObject{
+		0: {ObjectName: "stale", FilePath: filepath.Join(localDir, "stale"), ObjectSize: 7, Status: TaskObjectStatusWaitForDownload},
+		1: {ObjectName: "new", FilePath: filepath.Join(localDir, "new"), ObjectSize: 7, Status: TaskObjectStatusWaitForDownload},
+		2: {ObjectName: "done", FilePath: filepath.Join(localDir, "done"), ObjectSize: 7, Status: TaskObjectStatusDownloaded},
+	}}
+	var (
+		lock       sync.Mutex
+		downloaded []string
+	)
+	gnfdClient := newFakeHashClient()
+	gnfdClient.headObject = func(bucketName, objectName string) (*sdktypes.ObjectDetail, error) {
+		return &sdktypes.ObjectDetail{ObjectInfo: &storageTypes.ObjectInfo{PayloadSize: 7, Checksums: [][]byte{[]byte("content")}}}, nil
+	}
+	gnfdClient.getObject = func(bucketName, objectName string, opts sdktypes.GetObjectOptions) (io.ReadCloser, error) {
+		lock.Lock()
+		downloaded = append(downloaded, objectName)
+		lock.Unlock()
+		return io.NopCloser(strings.NewReader("content")), nil
+	}
+
+	if err := downloadFolderByTask(homeDir, gnfdClient, taskState, 2); err == nil {
+		t.Fatal("expected an error of the stale file")
+	}
+	if content, _ := os.ReadFile(filepath.Join(localDir, "stale")); string(content) != "Content" {
+		t.Errorf("got the stale file %q, expected it is kept", content)
+	}
+	wantStatus := []string{TaskObjectStatusFailed, TaskObjectStatusDownloaded, TaskObjectStatusDownloaded}
+	for index, want := range wantStatus {
+		if status := taskState.GetObjectStatus(index); status != want {
+			t.Errorf("got the status %s of object %d, expected %s", status, index, want)
+		}
+	}
+	if len(downloaded) != 1 || downloaded[0] != "new" {
+		t.Errorf("got the downloaded objects %v, expected [new]", downloaded)
+	}
+}
@@ -75,15 +75,26 @@ func buyQuotaForBucket(ctx *cli.Context) error {
 	txnHash, err := client.BuyQuotaForBucket(c, bucketName, targetQuota, sdktypes.BuyQuotaOption{TxOpts: &TxnOptionWithSyncMode})
 
 	if err != nil {
-		fmt.Println("buy quota error:", err.Error())
-		return nil
+		return toCmdErr(err)
 	}
 
-	fmt.Printf("buy quota for bucket: %s \n", bucketName)
-	fmt.Println("transaction hash: ", txnHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "buy_quota", Resource: bucketName, TxnHash: txnHash})
+	}
+	fmt.Fprintf(infoWriter, "buy quota for bucket: %s \n", bucketName)
+	fmt.Fprintln(infoWriter, "transaction hash: ", txnHash)
 	return nil
 }
 
+// quotaRecord is the record of the read quota queried by "payment get-quota", the quotas are in bytes
+type quotaRecord struct {
+	BucketName           string `json:"bucket_name"`
+	ChargedQuota         uint64 `json:"charged_quota"`
+	RemainedFreeQuota    uint64 `json:"remained_free_quota"`
+	ConsumedChargedQuota uint64 `json:"consumed_charged_quota"`
+	ConsumedFreeQuota    uint64 `json:"consumed_free_quota"`
+}
+
 // getQuotaInfo query the quota price info of sp from greenfield chain
 func getQuotaInfo(ctx *cli.Context) error {
 	bucketName, err := getBucketNameByUrl(ctx)
@@ -110,14 +121,24 @@ func getQuotaInfo(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		return printRecord(quotaRecord{
+			BucketName:           bucketName,
+			ChargedQuota:         quotaInfo.ReadQuotaSize,
+			RemainedFreeQuota:    quotaInfo.SPFreeReadQuotaSize,
+			ConsumedChargedQuota: quotaInfo.ReadConsumedSize,
+			ConsumedFreeQuota:    quotaInfo.FreeConsumedSize,
+		})
+	}
+
 	nameMaxLen := len("consumed charged quota:")
 	format := fmt.Sprintf("%%-%ds %%-%dd   \n", nameMaxLen, 50)
 	firstLineFormat := fmt.Sprintf("%%-%ds %%-%ds  \n", nameMaxLen, 50)
-	fmt.Printf(firstLineFormat, "quota name", "quota value")
-	fmt.Printf(format, "charged quota:", quotaInfo.ReadQuotaSize)
-	fmt.Printf(format, "remained free quota:", quotaInfo.SPFreeReadQuotaSize)
-	fmt.Printf(format, "consumed charged quota:", quotaInfo.ReadConsumedSize)
-	fmt.Printf(format, "consumed free quota:", quotaInfo.FreeConsumedSize)
+	fmt.Fprintf(infoWriter, firstLineFormat, "quota name", "quota value")
+	fmt.Fprintf(infoWriter, format, "charged quota:", quotaInfo.ReadQuotaSize)
+	fmt.Fprintf(infoWriter, format, "remained free quota:", quotaInfo.SPFreeReadQuotaSize)
+	fmt.Fprintf(infoWriter, format, "consumed charged quota:", quotaInfo.ReadConsumedSize)
+	fmt.Fprintf(infoWriter, format, "consumed free quota:", quotaInfo.FreeConsumedSize)
 
 	return nil
 }
@@ -48,7 +48,10 @@ func CreatePaymentAccount(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Printf("create payment account for %s succ, txHash: %s\n", acc.GetAddress().String(), txHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "create_payment_account", Resource: acc.GetAddress().String(), TxnHash: txHash})
+	}
+	fmt.Fprintf(infoWriter, "create payment account for %s succ, txHash: %s\n", acc.GetAddress().String(), txHash)
 	return nil
 }
 
@@ -86,7 +89,10 @@ func Deposit(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	toAddr := ctx.String(toAddressFlag)
+	toAddr, err := getAddressFlag(ctx, toAddressFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	_, err = sdk.AccAddressFromHexUnsafe(toAddr)
 	if err != nil {
 		return toCmdErr(err)
@@ -108,7 +114,10 @@ func Deposit(ctx *cli.Context) error {
 	if err != nil {
 		return toCmdErr(err)
 	}
-	fmt.Printf("Deposit %s BNB to payment account %s succ, txHash=%s\n", amount.String(), toAddr, txHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "deposit", Resource: toAddr, Amount: amount.String(), TxnHash: txHash})
+	}
+	fmt.Fprintf(infoWriter, "Deposit %s BNB to payment account %s succ, txHash=%s\n", amount.String(), toAddr, txHash)
 	return nil
 }
 
@@ -146,7 +155,10 @@ func Withdraw(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fromAddr := ctx.String(fromAddressFlag)
+	fromAddr, err := getAddressFlag(ctx, fromAddressFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	_, err = sdk.AccAddressFromHexUnsafe(fromAddr)
 	if err != nil {
 		return toCmdErr(err)
@@ -169,7 +181,10 @@ func Withdraw(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
-	fmt.Printf("Withdraw %s from %s succ, txHash=%s\n", amount.String(), fromAddr, txHash)
+	if isStructuredOutput() {
+		return printRecord(txnRecord{Action: "withdraw", Resource: fromAddr, Amount: amount.String(), TxnHash: txHash})
+	}
+	fmt.Fprintf(infoWriter, "Withdraw %s from %s succ, txHash=%s\n", amount.String(), fromAddr, txHash)
 	return nil
 }
 
@@ -205,7 +220,10 @@ func listPaymentAccounts(ctx *cli.Context) error {
 	defer cancelCreateBucket()
 
 	var ownerAddr string
-	ownerAddrStr := ctx.String(ownerAddressFlag)
+	ownerAddrStr, err := getAddressFlag(ctx, ownerAddressFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	if ownerAddrStr != "" {
 		_, err = sdk.AccAddressFromHexUnsafe(ownerAddrStr)
 		if err != nil {
@@ -222,18 +240,28 @@ func listPaymentAccounts(ctx *cli.Context) error {
 	accounts, err := client.GetPaymentAccountsByOwner(c, ownerAddr)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			fmt.Println("Accounts not exist")
+			if !isStructuredOutput() {
+				fmt.Fprintln(infoWriter, "Accounts not exist")
+			}
 			return nil
 		}
 		return toCmdErr(err)
 	}
+	if isStructuredOutput() {
+		for _, a := range accounts {
+			if err = printRecord(a); err != nil {
+				return toCmdErr(err)
+			}
+		}
+		return nil
+	}
 	if len(accounts) == 0 {
-		fmt.Println("Accounts not exist")
+		fmt.Fprintln(infoWriter, "Accounts not exist")
 		return nil
 	}
-	fmt.Println("payment accounts list:")
+	fmt.Fprintln(infoWriter, "payment accounts list:")
 	for i, a := range accounts {
-		fmt.Printf("%d: %s \n", i+1, a)
+		fmt.Fprintf(infoWriter, "%d: %s \n", i+1, a)
 	}
 	return nil
 }
@@ -71,10 +71,9 @@ $ gnfd-cmd policy put --groupId 111 --actions get,delete grn:o::gnfd-bucket/gnfd
 				},
 				Usage: "set the effect of the policy",
 			},
-			&cli.Uint64Flag{
+			&cli.StringFlag{
 				Name:  expireTimeFlag,
-				Value: 0,
-				Usage: "set the expire unix time stamp of the policy",
+				Usage: "set the expire time of the policy, it can be a unix timestamp, RFC3339 or in the format of the timeFormat flag",
 			},
 		},
 	}
@@ -178,10 +177,13 @@ func putPolicy(ctx *cli.Context) error {
 		}
 	}
 
-	expireTime := ctx.Uint64(expireTimeFlag)
+	expireTime, err := getTimeFlag(ctx, expireTimeFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	var statement permTypes.Statement
 	if expireTime > 0 {
-		tm := time.Unix(int64(expireTime), 0)
+		tm := time.Unix(expireTime, 0)
 		if bucketNameOfBucketPolicy != "" && isObjectActionInBucketPolicy {
 			// putting bucket policy need to set the sub-resource as "grn:o:bucket-name/*"
 			statement = utils.NewStatement(actions, effect, []string{gnfdTypes.NewObjectGRN(bucketNameOfBucketPolicy, "*").String()}, sdktypes.NewStatementOptions{StatementExpireTime: &tm})
@@ -233,7 +235,10 @@ func handlePutPolicy(ctx *cli.Context, resource string, statements []*permTypes.
 		return err
 	}
 	groupId := ctx.Uint64(groupIDFlag)
-	grantee := ctx.String(granteeFlag)
+	grantee, err := getAddressFlag(ctx, granteeFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 
 	if policyType == BucketResourceType {
 		bucketName, err := parseBucketResource(resource)
@@ -284,7 +289,10 @@ func handleDeletePolicy(ctx *cli.Context, resource string, policyType ResourceTy
 		return err
 	}
 	groupId := ctx.Uint64(groupIDFlag)
-	grantee := ctx.String(granteeFlag)
+	grantee, err := getAddressFlag(ctx, granteeFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 
 	if policyType == BucketResourceType {
 		bucketName, err := parseBucketResource(resource)
@@ -337,7 +345,10 @@ func handleListPolicy(ctx *cli.Context, resource string, policyType ResourceType
 		return err
 	}
 	//groupId := ctx.Uint64(groupIDFlag)
-	grantee := ctx.String(granteeFlag)
+	grantee, err := getAddressFlag(ctx, granteeFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 
 	if policyType == BucketResourceType {
 		bucketName, err := parseBucketResource(resource)
@@ -372,7 +383,9 @@ func handleListPolicy(ctx *cli.Context, resource string, policyType ResourceType
 			return toCmdErr(err)
 		}
 
-		listPolicyInfo(0, grantee, resource, *policyInfo)
+		if err = listPolicyInfo(0, grantee, resource, *policyInfo); err != nil {
+			return toCmdErr(err)
+		}
 	}
 
 	return nil
@@ -391,14 +404,18 @@ func handleObjectPolicy(ctx *cli.Context, client client.IClient, bucketName, obj
 		if err != nil {
 			return toCmdErr(err)
 		}
-		fmt.Printf("put policy of the object:%s succ, txn hash: %s\n", objectName, policyTx)
+		if !isStructuredOutput() {
+			fmt.Fprintf(infoWriter, "put policy of the object:%s succ, txn hash: %s\n", objectName, policyTx)
+		}
 	} else {
 		policyTx, err = client.DeleteObjectPolicy(c, bucketName, objectName, principal,
 			sdktypes.DeletePolicyOption{TxOpts: &types.TxOption{Mode: &SyncBroadcastMode}})
 		if err != nil {
 			return toCmdErr(err)
 		}
-		fmt.Printf("delete policy of the object:%s succ, txn hash: %s\n", objectName, policyTx)
+		if !isStructuredOutput() {
+			fmt.Fprintf(infoWriter, "delete policy of the object:%s succ, txn hash: %s\n", objectName, policyTx)
+		}
 	}
 
 	err = waitTxnStatus(client, c, policyTx, "objectPolicy")
@@ -406,6 +423,14 @@ func handleObjectPolicy(ctx *cli.Context, client client.IClient, bucketName, obj
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		action := "put_object_policy"
+		if delete {
+			action = "delete_object_policy"
+		}
+		return printRecord(txnRecord{Action: action, Resource: bucketName + "/" + objectName, TxnHash: policyTx})
+	}
+
 	// print object policy info after updated
 	printObjectPolicy(ctx, client, bucketName, objectName)
 
@@ -425,14 +450,18 @@ func handleBucketPolicy(ctx *cli.Context, client client.IClient, bucketName stri
 		if err != nil {
 			return toCmdErr(err)
 		}
-		fmt.Printf("put policy of the bucket:%s succ, txn hash: %s\n", bucketName, policyTx)
+		if !isStructuredOutput() {
+			fmt.Fprintf(infoWriter, "put policy of the bucket:%s succ, txn hash: %s\n", bucketName, policyTx)
+		}
 
 	} else {
 		policyTx, err = client.DeleteBucketPolicy(c, bucketName, principal, sdktypes.DeletePolicyOption{TxOpts: &TxnOptionWithSyncMode})
 		if err != nil {
 			return toCmdErr(err)
 		}
-		fmt.Printf("delete policy of the bucket:%s succ, txn hash: %s\n", bucketName, policyTx)
+		if !isStructuredOutput() {
+			fmt.Fprintf(infoWriter, "delete policy of the bucket:%s succ, txn hash: %s\n", bucketName, policyTx)
+		}
 	}
 
 	err = waitTxnStatus(client, c, policyTx, "bucketPolicy")
@@ -440,6 +469,14 @@ func handleBucketPolicy(ctx *cli.Context, client client.IClient, bucketName stri
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		action := "put_bucket_policy"
+		if delete {
+			action = "delete_bucket_policy"
+		}
+		return printRecord(txnRecord{Action: action, Resource: bucketName, TxnHash: policyTx})
+	}
+
 	// print bucket policy info after updated
 	printBucketPolicy(ctx, client, bucketName)
 
@@ -451,25 +488,31 @@ func handleGroupPolicy(ctx *cli.Context, client client.IClient, groupName string
 	c, cancelPolicy := context.WithCancel(globalContext)
 	defer cancelPolicy()
 
-	grantee := ctx.String(granteeFlag)
+	grantee, err := getAddressFlag(ctx, granteeFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 	if grantee == "" {
 		return errors.New("grantee need to be set when put group policy")
 	}
 	var policyTx string
-	var err error
 	if !delete {
 		policyTx, err = client.PutGroupPolicy(c, groupName, grantee, statements,
 			sdktypes.PutPolicyOption{TxOpts: &TxnOptionWithSyncMode})
 		if err != nil {
 			return toCmdErr(err)
 		}
-		fmt.Printf("put policy of the group:%s succ, txn hash: %s\n", groupName, policyTx)
+		if !isStructuredOutput() {
+			fmt.Fprintf(infoWriter, "put policy of the group:%s succ, txn hash: %s\n", groupName, policyTx)
+		}
 	} else {
 		policyTx, err = client.DeleteGroupPolicy(c, groupName, grantee, sdktypes.DeletePolicyOption{TxOpts: &TxnOptionWithSyncMode})
 		if err != nil {
 			return toCmdErr(err)
 		}
-		fmt.Printf("delete policy of the group:%s succ, txn hash: %s\n", groupName, policyTx)
+		if !isStructuredOutput() {
+			fmt.Fprintf(infoWriter, "delete policy of the group:%s succ, txn hash: %s\n", groupName, policyTx)
+		}
 	}
 
 	err = waitTxnStatus(client, c, policyTx, "groupPolicy")
@@ -477,9 +520,17 @@ func handleGroupPolicy(ctx *cli.Context, client client.IClient, groupName string
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		action := "put_group_policy"
+		if delete {
+			action = "delete_group_policy"
+		}
+		return printRecord(txnRecord{Action: action, Resource: groupName, TxnHash: policyTx})
+	}
+
 	policyInfo, err := client.GetGroupPolicy(c, groupName, grantee)
 	if err == nil {
-		fmt.Printf("latest group policy info:  \n %s\n", policyInfo.String())
+		fmt.Fprintf(infoWriter, "latest group policy info:  \n %s\n", policyInfo.String())
 	}
 
 	return nil
@@ -531,18 +582,19 @@ func parseGroupResource(resourceName string) (string, string, error) {
 func printObjectPolicy(ctx *cli.Context, cli client.IClient, bucketName, objectName string) {
 	// get the latest policy from chain
 	groupId := ctx.Uint64(groupIDFlag)
-	grantee := ctx.String(granteeFlag)
+	// the grantee has been checked by the handler of the command
+	grantee, _ := getAddressFlag(ctx, granteeFlag)
 	c, cancelPolicy := context.WithCancel(globalContext)
 	defer cancelPolicy()
 	if groupId > 0 {
 		policyInfo, err := cli.GetObjectPolicyOfGroup(c, bucketName, objectName, groupId)
 		if err == nil {
-			fmt.Printf("latest object policy info: \n %s\n", policyInfo.String())
+			fmt.Fprintf(infoWriter, "latest object policy info: \n %s\n", policyInfo.String())
 		}
 	} else {
 		policyInfo, err := cli.GetObjectPolicy(c, bucketName, objectName, grantee)
 		if err == nil {
-			fmt.Printf("latest object policy info:  \n %s\n", policyInfo.String())
+			fmt.Fprintf(infoWriter, "latest object policy info:  \n %s\n", policyInfo.String())
 		}
 	}
 }
@@ -550,7 +602,10 @@ func printObjectPolicy(ctx *cli.Context, cli client.IClient, bucketName, objectN
 func listObjectPolicy(ctx *cli.Context, cli client.IClient, bucketName, objectName, resourceName string) error {
 	// get the latest policy from chain
 	groupId := ctx.Uint64(groupIDFlag)
-	grantee := ctx.String(granteeFlag)
+	grantee, err := getAddressFlag(ctx, granteeFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 
 	if groupId == 0 && grantee == "" {
 		return toCmdErr(errors.New("failed to parse group id or grantee info"))
@@ -558,7 +613,6 @@ func listObjectPolicy(ctx *cli.Context, cli client.IClient, bucketName, objectNa
 	c, cancelPolicy := context.WithCancel(globalContext)
 	defer cancelPolicy()
 	var policyInfo *permTypes.Policy
-	var err error
 	if groupId > 0 {
 		policyInfo, err = cli.GetObjectPolicyOfGroup(c, bucketName, objectName, groupId)
 	} else {
@@ -568,8 +622,7 @@ func listObjectPolicy(ctx *cli.Context, cli client.IClient, bucketName, objectNa
 		return err
 	}
 
-	listPolicyInfo(groupId, grantee, resourceName, *policyInfo)
-	return nil
+	return listPolicyInfo(groupId, grantee, resourceName, *policyInfo)
 }
 
 func printBucketPolicy(ctx *cli.Context, cli client.IClient, bucketName string) {
@@ -577,16 +630,17 @@ func printBucketPolicy(ctx *cli.Context, cli client.IClient, bucketName string)
 	defer cancelPolicy()
 	// get the latest policy from chain
 	groupId := ctx.Uint64(groupIDFlag)
-	grantee := ctx.String(granteeFlag)
+	// the grantee has been checked by the handler of the command
+	grantee, _ := getAddressFlag(ctx, granteeFlag)
 	if groupId > 0 {
 		policyInfo, err := cli.GetBucketPolicyOfGroup(c, bucketName, groupId)
 		if err == nil {
-			fmt.Printf("latest bucket policy info: \n %s\n", policyInfo.String())
+			fmt.Fprintf(infoWriter, "latest bucket policy info: \n %s\n", policyInfo.String())
 		}
 	} else {
 		policyInfo, err := cli.GetBucketPolicy(c, bucketName, grantee)
 		if err == nil {
-			fmt.Printf("latest bucket policy info:  \n %s\n", policyInfo.String())
+			fmt.Fprintf(infoWriter, "latest bucket policy info:  \n %s\n", policyInfo.String())
 		}
 	}
 }
@@ -596,10 +650,12 @@ func listBucketPolicy(ctx *cli.Context, cli client.IClient, bucketName, resource
 	defer cancelPolicy()
 
 	groupId := ctx.Uint64(groupIDFlag)
-	grantee := ctx.String(granteeFlag)
+	grantee, err := getAddressFlag(ctx, granteeFlag)
+	if err != nil {
+		return toCmdErr(err)
+	}
 
 	var policyInfo *permTypes.Policy
-	var err error
 	if groupId > 0 {
 		policyInfo, err = cli.GetBucketPolicyOfGroup(c, bucketName, groupId)
 	} else {
@@ -609,8 +665,7 @@ func listBucketPolicy(ctx *cli.Context, cli client.IClient, bucketName, resource
 		return err
 	}
 
-	listPolicyInfo(groupId, grantee, resourceName, *policyInfo)
-	return nil
+	return listPolicyInfo(groupId, grantee, resourceName, *policyInfo)
 }
 
 func parseResourceType(resource string) (ResourceType, error) {
@@ -639,7 +694,39 @@ func getActionStr(actions []permTypes.ActionType) string {
 	return action
 }
 
-func listPolicyInfo(groupId uint64, grantee, resourceName string, policyInfo permTypes.Policy) {
+// policyRecord is the record of the policy statements listed by "policy ls"
+type policyRecord struct {
+	PolicyID  string   `json:"policy_id"`
+	Principal string   `json:"principal"`
+	Actions   []string `json:"actions"`
+	Effect    string   `json:"effect"`
+	Resource  string   `json:"resource"`
+}
+
+func listPolicyInfo(groupId uint64, grantee, resourceName string, policyInfo permTypes.Policy) error {
+	principal := grantee
+	if groupId > 0 {
+		principal = "groupID-" + strconv.FormatUint(groupId, 10)
+	}
+	if isStructuredOutput() {
+		for _, statement := range policyInfo.Statements {
+			actions := make([]string, 0, len(statement.GetActions()))
+			for _, action := range statement.GetActions() {
+				actions = append(actions, action.String()[len("ACTION_"):])
+			}
+			if err := printRecord(policyRecord{
+				PolicyID:  policyInfo.Id.String(),
+				Principal: principal,
+				Actions:   actions,
+				Effect:    statement.GetEffect().String()[len("EFFECT_"):],
+				Resource:  resourceName,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	var format string
 	if groupId > 0 {
 		format = fmt.Sprintf("%%-%ds %%-%ds %%-%ds %%-%ds  \n", 15, 40, 10, 20)
@@ -647,14 +734,11 @@ func listPolicyInfo(groupId uint64, grantee, resourceName string, policyInfo per
 		format = fmt.Sprintf("%%-%ds %%-%ds %%-%ds %%-%ds  \n", operatorAddressLen+10, 40, 10, 20)
 	}
 
-	fmt.Printf(format, "principal", "actions", "effect", "resource")
+	fmt.Fprintf(infoWriter, format, "principal", "actions", "effect", "resource")
 	for _, statement := range policyInfo.Statements {
 		actionName := getActionStr(statement.GetActions())
 		effectName := statement.GetEffect().String()[len("EFFECT_"):]
-		if groupId > 0 {
-			fmt.Printf(format, "groupID-"+strconv.FormatUint(groupId, 10), actionName, effectName, resourceName)
-		} else {
-			fmt.Printf(format, ""+grantee, actionName, effectName, resourceName)
-		}
+		fmt.Fprintf(infoWriter, format, principal, actionName, effectName, resourceName)
 	}
+	return nil
 }
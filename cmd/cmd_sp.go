@@ -55,6 +55,14 @@ $ gnfd-cmd sp get-price https://gnfd-testnet-sp-1.nodereal.io`,
 	}
 }
 
+// spListRecord is the record of the storage providers listed by "sp ls"
+type spListRecord struct {
+	Name            string `json:"name"`
+	OperatorAddress string `json:"operator_address"`
+	Endpoint        string `json:"endpoint"`
+	Status          string `json:"status"`
+}
+
 func ListSP(ctx *cli.Context) error {
 	client, err := NewClient(ctx, ClientOptions{IsQueryCmd: true})
 	if err != nil {
@@ -66,14 +74,27 @@ func ListSP(ctx *cli.Context) error {
 
 	spInfo, err := client.ListStorageProviders(c, false)
 	if err != nil {
-		fmt.Println("fail to list SP:", err.Error())
-		return nil
+		return toCmdErr(fmt.Errorf("fail to list SP: %v", err))
 	}
 
 	if len(spInfo) == 0 {
 		return nil
 	}
 
+	if isStructuredOutput() {
+		for _, info := range spInfo {
+			if err = printRecord(spListRecord{
+				Name:            info.Description.GetMoniker(),
+				OperatorAddress: info.OperatorAddress,
+				Endpoint:        info.Endpoint,
+				Status:          info.Status.String()[len(StatusSPrefix):],
+			}); err != nil {
+				return toCmdErr(err)
+			}
+		}
+		return nil
+	}
+
 	var nameMaxLen int
 	var endpointMaxLen int
 	for _, info := range spInfo {
@@ -88,9 +109,9 @@ func ListSP(ctx *cli.Context) error {
 
 	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds %%-%ds  \n", nameMaxLen, operatorAddressLen, endpointMaxLen, len(exitStatus))
 
-	fmt.Printf(format, "name", "operator address", "endpoint", "status")
+	fmt.Fprintf(infoWriter, format, "name", "operator address", "endpoint", "status")
 	for _, info := range spInfo {
-		fmt.Printf(format, info.Description.GetMoniker(), info.OperatorAddress, info.Endpoint, info.Status.String()[len(StatusSPrefix):])
+		fmt.Fprintf(infoWriter, format, info.Description.GetMoniker(), info.OperatorAddress, info.Endpoint, info.Status.String()[len(StatusSPrefix):])
 	}
 
 	return nil
@@ -121,12 +142,25 @@ func querySP(ctx *cli.Context) error {
 		return toCmdErr(errors.New("fail to get SP info"))
 	}
 
-	fmt.Println("SP info:")
-	fmt.Println(spInfo.String())
-	fmt.Println("Status:", spInfo.Status)
+	if isStructuredOutput() {
+		return printRecord(spInfo)
+	}
+
+	fmt.Fprintln(infoWriter, "SP info:")
+	fmt.Fprintln(infoWriter, spInfo.String())
+	fmt.Fprintln(infoWriter, "Status:", spInfo.Status)
 	return nil
 }
 
+// spPriceRecord is the record of the prices queried by "sp get-price", the prices are decimal strings in wei/byte
+// so that they are not rounded
+type spPriceRecord struct {
+	SPAddress     string `json:"sp_address"`
+	ReadPrice     string `json:"read_price"`
+	StorePrice    string `json:"store_price"`
+	FreeReadQuota uint64 `json:"free_read_quota"`
+}
+
 // getQuotaPrice query the quota price info of sp from greenfield chain
 func getQuotaPrice(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
@@ -152,21 +186,28 @@ func getQuotaPrice(ctx *cli.Context) error {
 		return toCmdErr(err)
 	}
 
+	if isStructuredOutput() {
+		return printRecord(spPriceRecord{
+			SPAddress:     spAddr.String(),
+			ReadPrice:     price.ReadPrice.String(),
+			StorePrice:    price.StorePrice.String(),
+			FreeReadQuota: price.FreeReadQuota,
+		})
+	}
+
 	quotaPrice, err := price.ReadPrice.Float64()
 	if err != nil {
-		fmt.Println("get quota price error:", err.Error())
-		return err
+		return toCmdErr(fmt.Errorf("get quota price error: %v", err))
 	}
 
 	storagePrice, err := price.StorePrice.Float64()
 	if err != nil {
-		fmt.Println("get storage price error:", err.Error())
-		return err
+		return toCmdErr(fmt.Errorf("get storage price error: %v", err))
 	}
 
-	fmt.Println("get bucket read quota price:", quotaPrice, " wei/byte")
-	fmt.Println("get bucket storage price:", storagePrice, " wei/byte")
-	fmt.Println("get bucket free quota:", price.FreeReadQuota)
+	fmt.Fprintln(infoWriter, "get bucket read quota price:", quotaPrice, " wei/byte")
+	fmt.Fprintln(infoWriter, "get bucket storage price:", storagePrice, " wei/byte")
+	fmt.Fprintln(infoWriter, "get bucket free quota:", price.FreeReadQuota)
 	return nil
 }
 
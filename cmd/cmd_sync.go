@@ -0,0 +1,516 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bnb-chain/greenfield-go-sdk/client"
+	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	storageTypes "github.com/bnb-chain/greenfield/x/storage/types"
+	"github.com/urfave/cli/v2"
+)
+
+// cmdSyncObjects return the command to synchronize a local directory with the objects under a prefix
+func cmdSyncObjects() *cli.Command {
+	return &cli.Command{
+		Name:      "sync",
+		Action:    syncObjects,
+		Usage:     "synchronize a local directory and the objects under a prefix in one direction",
+		ArgsUsage: "SOURCE TARGET",
+		Description: `
+Synchronize the files of a local directory to the objects under a bucket prefix, or synchronize the objects
+under a bucket prefix to a local directory. The direction is decided by which argument is the "gnfd://" url.
+
+A file is treated as changed if its size is different from the payload size of the object, or if the integrity
+hash computed locally is different from the checksums of the object on chain. Only the new or changed files are
+transferred. Since the object can not be overwritten or renamed, the changed object is deleted before uploading the
+new content. The local file is checked before deleting the object, but if the upload still fails after deleting,
+the remote copy is lost, the sync reports it as "lost" and the file should be synchronized again.
+If the delete flag is set, the objects (or local files) which do not exist in the source will be deleted.
+
+Examples:
+# upload the new or changed files of the local directory to the prefix
+$ gnfd-cmd object sync ./local-dir gnfd://gnfd-bucket/prefix
+# download the new or changed objects of the prefix to the local directory and delete the local files not in the prefix
+$ gnfd-cmd object sync --delete gnfd://gnfd-bucket/prefix ./local-dir`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  deleteFlag,
+				Value: false,
+				Usage: "delete the objects or local files which do not exist in the source",
+			},
+			&cli.GenericFlag{
+				Name: visibilityFlag,
+				Value: &CmdEnumValue{
+					Enum:    []string{publicReadType, privateType, inheritType},
+					Default: inheritType,
+				},
+				Usage:   "set visibility of the uploaded objects",
+				EnvVars: []string{objectVisibilityEnv},
+			},
+			&cli.Uint64Flag{
+				Name: partSizeFlag,
+				// the default part size is 32M
+				Value: 32 * 1024 * 1024,
+				Usage: "indicate the resumable upload 's part size, uploading a large file in multiple parts. " +
+					"The part size is an integer multiple of the segment size.",
+				EnvVars: []string{partSizeEnv},
+			},
+			&cli.BoolFlag{
+				Name:  bypassSealFlag,
+				Value: false,
+				Usage: "if set this flag as true, it will not wait for the file to be sealed after the uploading is completed.",
+			},
+		},
+	}
+}
+
+// syncStats records the result of the synchronization
+type syncStats struct {
+	transferred int
+	deleted     int
+	skipped     int
+	failed      int
+	// lost is the number of the changed objects which have been deleted but the new content failed to be uploaded
+	lost int
+}
+
+func (s syncStats) String() string {
+	return fmt.Sprintf("transferred: %d, deleted: %d, up to date: %d, failed: %d, lost: %d",
+		s.transferred, s.deleted, s.skipped, s.failed, s.lost)
+}
+
+// syncRecord is the record of the result of "object sync"
+type syncRecord struct {
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	Transferred int    `json:"transferred"`
+	Deleted     int    `json:"deleted"`
+	UpToDate    int    `json:"up_to_date"`
+	Failed      int    `json:"failed"`
+	Lost        int    `json:"lost"`
+}
+
+func syncObjects(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return toCmdErr(fmt.Errorf("args number should be 2"))
+	}
+
+	source := ctx.Args().Get(0)
+	target := ctx.Args().Get(1)
+	isUpload := strings.HasPrefix(target, "gnfd://")
+	if isUpload == strings.HasPrefix(source, "gnfd://") {
+		return toCmdErr(errors.New("one of the source and target should be the local directory and the other one should be the gnfd:// url"))
+	}
+
+	gnfdClient, err := NewClient(ctx, ClientOptions{IsQueryCmd: false})
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	c, cancelSync := context.WithCancel(globalContext)
+	defer cancelSync()
+
+	var stats syncStats
+	if isUpload {
+		stats, err = syncLocalToRemote(ctx, c, gnfdClient, source, target)
+	} else {
+		stats, err = syncRemoteToLocal(ctx, c, gnfdClient, source, target)
+	}
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	if isStructuredOutput() {
+		if err = printRecord(syncRecord{Source: source, Target: target, Transferred: stats.transferred, Deleted: stats.deleted,
+			UpToDate: stats.skipped, Failed: stats.failed, Lost: stats.lost}); err != nil {
+			return toCmdErr(err)
+		}
+	} else {
+		fmt.Fprintln(infoWriter, "sync finished,", stats.String())
+	}
+	if stats.lost > 0 {
+		return toCmdErr(fmt.Errorf("%d files failed to be synchronized, the remote objects of %d changed files have been deleted "+
+			"without uploading the new content, run the sync again to upload them", stats.failed, stats.lost))
+	}
+	if stats.failed > 0 {
+		return toCmdErr(fmt.Errorf("%d files failed to be synchronized", stats.failed))
+	}
+	return nil
+}
+
+// listObjectsByPrefix list all the objects under the prefix and return a map from object name to the object info
+func listObjectsByPrefix(c context.Context, gnfdClient client.IClient, bucketName, prefixName string) (map[string]*storageTypes.ObjectInfo, error) {
+	objects := make(map[string]*storageTypes.ObjectInfo)
+	var continuationToken string
+	for {
+		listResult, err := gnfdClient.ListObjects(c, bucketName, sdktypes.ListObjectsOptions{ShowRemovedObject: false,
+			MaxKeys:           defaultMaxKey,
+			ContinuationToken: continuationToken,
+			Prefix:            prefixName})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range listResult.Objects {
+			objects[object.ObjectInfo.ObjectName] = object.ObjectInfo
+		}
+
+		if !listResult.IsTruncated {
+			break
+		}
+		continuationToken = listResult.NextContinuationToken
+	}
+	return objects, nil
+}
+
+// isFileChanged check if the local file is different from the object by the size and the integrity hash
+func isFileChanged(c context.Context, gnfdClient client.IClient, hashes *fileHashes, objectInfo *storageTypes.ObjectInfo) (bool, error) {
+	if int64(objectInfo.PayloadSize) != hashes.size {
+		return true, nil
+	}
+
+	objectDetail, err := gnfdClient.HeadObject(c, objectInfo.BucketName, objectInfo.ObjectName)
+	if err != nil {
+		return false, err
+	}
+	return !isChecksumsEqual(hashes.checksums, objectDetail.ObjectInfo.Checksums), nil
+}
+
+// getSyncUploadFlag return the options of uploading the files from the flags of the sync command
+func getSyncUploadFlag(ctx *cli.Context) (UploadFlag, error) {
+	visibility, err := getVisibilityType(fmt.Sprintf("%s", ctx.Generic(visibilityFlag)))
+	if err != nil {
+		return UploadFlag{}, err
+	}
+	return UploadFlag{PartSize: ctx.Uint64(partSizeFlag), Visibility: visibility}, nil
+}
+
+// checkFileBeforeReplace check that the local file can be uploaded before the changed object is deleted,
+// the file should be readable, not too large and not be changing since its integrity hash is computed
+func checkFileBeforeReplace(filePath string, fileSize int64, hashes *fileHashes) error {
+	if fileSize > maxFileSize {
+		return fmt.Errorf("the size of %s is more than the max object size %d", filePath, int64(maxFileSize))
+	}
+	if info, err := os.Stat(filePath); err != nil || info.Size() != fileSize || hashes.size != fileSize {
+		return fmt.Errorf("the file %s is being changed", filePath)
+	}
+	return nil
+}
+
+// createObjectByHashes create the object on chain by the integrity hash computed for the comparison,
+// so that the file is not read again to create the object before uploading its payload
+func createObjectByHashes(c context.Context, gnfdClient client.IClient, bucketName, objectName, filePath string,
+	uploadFlag UploadFlag, hashes *fileHashes) error {
+	object := &UploadTaskObject{BucketName: bucketName, ObjectName: objectName, FilePath: filePath, ObjectSize: hashes.size}
+	msgs, err := buildCreateObjectMsgsByHashes(c, gnfdClient, object, uploadFlag, hashes)
+	if err != nil {
+		return err
+	}
+	_, _, err = broadcastMsgsAndWait(gnfdClient, c, msgs, "CreateObject")
+	return err
+}
+
+// skipUnreadableEntry count the entry which can not be read by the walk as failed and skip it, so that the other
+// entries are still synced. The error of the local directory itself is returned as nothing can be synced.
+func skipUnreadableEntry(localDir, path string, info os.FileInfo, err error, stats *syncStats) error {
+	if filepath.Clean(path) == filepath.Clean(localDir) {
+		return err
+	}
+	fmt.Fprintf(infoWriter, "failed to read %s: %v\n", path, err)
+	stats.failed++
+	if info != nil && info.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+// isObjectUnderPaths return true if the object is the path or under the path of the objects, the paths end without "/"
+func isObjectUnderPaths(objectName string, paths []string) bool {
+	for _, path := range paths {
+		if objectName == path || strings.HasPrefix(objectName, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSyncUrl parse the bucket name and the prefix which ends with "/" from the url
+func parseSyncUrl(urlInfo string) (string, string, error) {
+	bucketName, prefixName, err := ParseBucketAndPrefix(urlInfo)
+	if err != nil {
+		return "", "", err
+	}
+	if bucketName == "" {
+		return "", "", errors.New("fail to parse bucket name")
+	}
+	if prefixName != "" && !strings.HasSuffix(prefixName, "/") {
+		prefixName = prefixName + "/"
+	}
+	return bucketName, prefixName, nil
+}
+
+// syncLocalToRemote upload the new or changed files of the local directory to the prefix
+func syncLocalToRemote(ctx *cli.Context, c context.Context, gnfdClient client.IClient, localDir, urlInfo string) (syncStats, error) {
+	var stats syncStats
+	bucketName, prefixName, err := parseSyncUrl(urlInfo)
+	if err != nil {
+		return stats, err
+	}
+
+	fileInfo, err := os.Stat(localDir)
+	if err != nil {
+		return stats, err
+	}
+	if !fileInfo.IsDir() {
+		return stats, fmt.Errorf("%s is not a directory", localDir)
+	}
+
+	if _, err = gnfdClient.HeadBucket(c, bucketName); err != nil {
+		return stats, headBucketErr(bucketName, err)
+	}
+
+	remoteObjects, err := listObjectsByPrefix(c, gnfdClient, bucketName, prefixName)
+	if err != nil {
+		return stats, err
+	}
+
+	uploadFlag, err := getSyncUploadFlag(ctx)
+	if err != nil {
+		return stats, err
+	}
+	bypassSeal := ctx.Bool(bypassSealFlag)
+	// the hashes are set if they have been computed to compare the file with the object, they are reused to create the object
+	upload := func(objectName, filePath string, isFolder bool, size int64, hashes *fileHashes) error {
+		if hashes != nil {
+			if err := createObjectByHashes(c, gnfdClient, bucketName, objectName, filePath, uploadFlag, hashes); err != nil {
+				return err
+			}
+		}
+		if err := uploadFileByTask(bucketName, objectName, filePath, uploadFlag, gnfdClient, isFolder, size, true); err != nil {
+			return err
+		}
+		// the empty objects and the folders are sealed when they are created
+		if bypassSeal || size == 0 {
+			return nil
+		}
+		return waitObjectSeal(c, gnfdClient, bucketName, objectName)
+	}
+
+	localObjects := make(map[string]bool)
+	// the remote objects of the unreadable entries are not deleted as they may still exist locally
+	var unreadablePaths []string
+	walkErr := filepath.Walk(localDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if relPath, relErr := filepath.Rel(localDir, path); relErr == nil {
+				unreadablePaths = append(unreadablePaths, prefixName+filepath.ToSlash(relPath))
+			}
+			return skipUnreadableEntry(localDir, path, info, err, &stats)
+		}
+		relPath, err := filepath.Rel(localDir, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		objectName := prefixName + filepath.ToSlash(relPath)
+		if info.IsDir() {
+			objectName = objectName + "/"
+		}
+		localObjects[objectName] = true
+
+		objectInfo, exist := remoteObjects[objectName]
+		if info.IsDir() {
+			if exist {
+				stats.skipped++
+				return nil
+			}
+			if uploadErr := upload(objectName, "", true, 0, nil); uploadErr != nil {
+				fmt.Fprintf(infoWriter, "failed to create folder %s: %v\n", objectName, uploadErr)
+				stats.failed++
+				return nil
+			}
+			fmt.Fprintf(infoWriter, "create folder: %s\n", objectName)
+			stats.transferred++
+			return nil
+		}
+
+		if exist {
+			if info.Size() > maxFileSize {
+				fmt.Fprintf(infoWriter, "skip the changed object %s: the size of %s is more than the max object size %d\n",
+					objectName, path, int64(maxFileSize))
+				stats.failed++
+				return nil
+			}
+			// the file is read once to compare it with the object, the hashes are reused to replace the changed object
+			hashes, hashErr := computeFileHashes(gnfdClient, path)
+			if hashErr != nil {
+				fmt.Fprintf(infoWriter, "failed to compare %s with object %s: %v\n", path, objectName, hashErr)
+				stats.failed++
+				return nil
+			}
+			changed, checkErr := isFileChanged(c, gnfdClient, hashes, objectInfo)
+			if checkErr != nil {
+				fmt.Fprintf(infoWriter, "failed to compare %s with object %s: %v\n", path, objectName, checkErr)
+				stats.failed++
+				return nil
+			}
+			if !changed && objectInfo.GetObjectStatus() == storageTypes.OBJECT_STATUS_SEALED {
+				stats.skipped++
+				return nil
+			}
+			if changed {
+				// the object can not be overwritten or renamed, so the old one is deleted before uploading,
+				// check the local file firstly so that the remote copy is not deleted for a file which can not be uploaded
+				if checkErr = checkFileBeforeReplace(path, info.Size(), hashes); checkErr != nil {
+					fmt.Fprintf(infoWriter, "skip the changed object %s: %v\n", objectName, checkErr)
+					stats.failed++
+					return nil
+				}
+				if _, deleteErr := deleteObjectAndWait(gnfdClient, c, bucketName, objectName); deleteErr != nil {
+					fmt.Fprintf(infoWriter, "failed to delete the changed object %s: %v\n", objectName, deleteErr)
+					stats.failed++
+					return nil
+				}
+				if uploadErr := upload(objectName, path, false, info.Size(), hashes); uploadErr != nil {
+					fmt.Fprintf(infoWriter, "DATA LOSS: the changed object %s has been deleted, but %s failed to be uploaded: %v\n"+
+						"the remote copy no longer exists, run the sync again to upload the file\n", objectName, path, uploadErr)
+					stats.lost++
+					stats.failed++
+					return nil
+				}
+				stats.transferred++
+				return nil
+			}
+		}
+
+		if uploadErr := upload(objectName, path, false, info.Size(), nil); uploadErr != nil {
+			fmt.Fprintf(infoWriter, "failed to upload %s to object %s: %v\n", path, objectName, uploadErr)
+			stats.failed++
+			return nil
+		}
+		stats.transferred++
+		return nil
+	})
+	if walkErr != nil {
+		return stats, walkErr
+	}
+
+	if ctx.Bool(deleteFlag) {
+		for objectName := range remoteObjects {
+			if localObjects[objectName] || objectName == prefixName || isObjectUnderPaths(objectName, unreadablePaths) {
+				continue
+			}
+			if _, deleteErr := deleteObjectAndWait(gnfdClient, c, bucketName, objectName); deleteErr != nil {
+				fmt.Fprintf(infoWriter, "failed to delete object %s: %v\n", objectName, deleteErr)
+				stats.failed++
+				continue
+			}
+			fmt.Fprintf(infoWriter, "delete: %s\n", objectName)
+			stats.deleted++
+		}
+	}
+	return stats, nil
+}
+
+// syncRemoteToLocal download the new or changed objects of the prefix to the local directory
+func syncRemoteToLocal(ctx *cli.Context, c context.Context, gnfdClient client.IClient, urlInfo, localDir string) (syncStats, error) {
+	var stats syncStats
+	bucketName, prefixName, err := parseSyncUrl(urlInfo)
+	if err != nil {
+		return stats, err
+	}
+
+	if err = os.MkdirAll(localDir, 0755); err != nil {
+		return stats, fmt.Errorf("failed to create local directory %s: %v", localDir, err)
+	}
+
+	if _, err = gnfdClient.HeadBucket(c, bucketName); err != nil {
+		return stats, headBucketErr(bucketName, err)
+	}
+
+	remoteObjects, err := listObjectsByPrefix(c, gnfdClient, bucketName, prefixName)
+	if err != nil {
+		return stats, err
+	}
+
+	remoteFiles := make(map[string]bool)
+	for objectName, objectInfo := range remoteObjects {
+		filePath, pathErr := getDownloadPathOfObject(localDir, prefixName, objectName)
+		if pathErr != nil {
+			fmt.Fprintf(infoWriter, "skip object %s: %v\n", objectName, pathErr)
+			continue
+		}
+		remoteFiles[filepath.Clean(filePath)] = true
+
+		if strings.HasSuffix(objectName, "/") {
+			if err = os.MkdirAll(filePath, 0755); err != nil {
+				return stats, fmt.Errorf("failed to create local directory %s: %v", filePath, err)
+			}
+			continue
+		}
+
+		if objectInfo.GetObjectStatus() != storageTypes.OBJECT_STATUS_SEALED {
+			fmt.Fprintf(infoWriter, "skip object %s which has not been sealed\n", objectName)
+			continue
+		}
+
+		if stat, statErr := os.Stat(filePath); statErr == nil && !stat.IsDir() {
+			// the file of a different size is changed, only the file of the same size is read to compare the hashes
+			changed := stat.Size() != int64(objectInfo.PayloadSize)
+			var checkErr error
+			if !changed {
+				var hashes *fileHashes
+				if hashes, checkErr = computeFileHashes(gnfdClient, filePath); checkErr == nil {
+					changed, checkErr = isFileChanged(c, gnfdClient, hashes, objectInfo)
+				}
+			}
+			if checkErr != nil {
+				fmt.Fprintf(infoWriter, "failed to compare %s with object %s: %v\n", filePath, objectName, checkErr)
+				stats.failed++
+				continue
+			}
+			if !changed {
+				stats.skipped++
+				continue
+			}
+		}
+
+		if downloadErr := downloadObjectToPath(c, gnfdClient, bucketName, objectName, filePath, int64(objectInfo.PayloadSize), true, false); downloadErr != nil {
+			fmt.Fprintf(infoWriter, "\nfailed to download object %s: %v\n", objectName, downloadErr)
+			stats.failed++
+			continue
+		}
+		fmt.Fprintf(infoWriter, "\ndownload object %s, the file path is %s\n", objectName, filePath)
+		stats.transferred++
+	}
+
+	if ctx.Bool(deleteFlag) {
+		walkErr := filepath.Walk(localDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return skipUnreadableEntry(localDir, path, info, err, &stats)
+			}
+			if info.IsDir() || remoteFiles[filepath.Clean(path)] {
+				return nil
+			}
+			if removeErr := os.Remove(path); removeErr != nil {
+				fmt.Fprintf(infoWriter, "failed to delete local file %s: %v\n", path, removeErr)
+				stats.failed++
+				return nil
+			}
+			fmt.Fprintf(infoWriter, "delete: %s\n", path)
+			stats.deleted++
+			return nil
+		})
+		if walkErr != nil {
+			return stats, walkErr
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSyncUrl(t *testing.T) {
+	tests := []struct {
+		url        string
+		bucketName string
+		prefixName string
+		wantErr    bool
+	}{
+		{url: "gnfd://bucket", bucketName: "bucket"},
+		{url: "gnfd://bucket/", bucketName: "bucket"},
+		{url: "gnfd://bucket/prefix", bucketName: "bucket", prefixName: "prefix/"},
+		{url: "gnfd://bucket/a/b/", bucketName: "bucket", prefixName: "a/b/"},
+		{url: "gnfd:///prefix", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			bucketName, prefixName, err := parseSyncUrl(tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s %s", bucketName, prefixName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if bucketName != tt.bucketName || prefixName != tt.prefixName {
+				t.Errorf("got (%s, %s), expected (%s, %s)", bucketName, prefixName, tt.bucketName, tt.prefixName)
+			}
+		})
+	}
+}
+
+func TestCheckFileBeforeReplace(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(filePath, []byte("content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filePath string
+		fileSize int64
+		hashSize int64
+		wantErr  bool
+	}{
+		{name: "unchanged", filePath: filePath, fileSize: 7, hashSize: 7},
+		{name: "changed after hashing", filePath: filePath, fileSize: 7, hashSize: 5, wantErr: true},
+		{name: "changed after listing", filePath: filePath, fileSize: 5, hashSize: 5, wantErr: true},
+		{name: "too large", filePath: filePath, fileSize: maxFileSize + 1, hashSize: maxFileSize + 1, wantErr: true},
+		{name: "removed", filePath: filePath + ".removed", fileSize: 7, hashSize: 7, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFileBeforeReplace(tt.filePath, tt.fileSize, &fileHashes{size: tt.hashSize})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got the error %v, expected an error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSkipUnreadableEntry(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	localDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(localDir, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(localDir, "file"), []byte("content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	dirInfo, err := os.Lstat(filepath.Join(localDir, "dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileInfo, err := os.Lstat(filepath.Join(localDir, "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	readErr := fs.ErrPermission
+
+	tests := []struct {
+		name string
+		path string
+		info os.FileInfo
+		want error
+	}{
+		{name: "unreadable directory", path: filepath.Join(localDir, "dir"), info: dirInfo, want: filepath.SkipDir},
+		{name: "unreadable file", path: filepath.Join(localDir, "file"), info: fileInfo},
+		{name: "entry failed to stat", path: filepath.Join(localDir, "missing")},
+		{name: "local directory", path: localDir + string(filepath.Separator), want: readErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stats syncStats
+			if err := skipUnreadableEntry(localDir, tt.path, tt.info, readErr, &stats); err != tt.want {
+				t.Errorf("got %v, expected %v", err, tt.want)
+			}
+			wantFailed := 1
+			if tt.want == readErr {
+				wantFailed = 0
+			}
+			if stats.failed != wantFailed {
+				t.Errorf("got %d failed, expected %d", stats.failed, wantFailed)
+			}
+		})
+	}
+}
+
+func TestIsObjectUnderPaths(t *testing.T) {
+	paths := []string{"prefix/dir", "prefix/file"}
+	tests := []struct {
+		objectName string
+		want       bool
+	}{
+		{objectName: "prefix/dir", want: true},
+		{objectName: "prefix/dir/", want: true},
+		{objectName: "prefix/dir/sub/file", want: true},
+		{objectName: "prefix/file", want: true},
+		{objectName: "prefix/file2", want: false},
+		{objectName: "prefix/directory/file", want: false},
+		{objectName: "prefix/", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.objectName, func(t *testing.T) {
+			if got := isObjectUnderPaths(tt.objectName, paths); got != tt.want {
+				t.Errorf("got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
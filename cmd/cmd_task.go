@@ -1,14 +1,35 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/bnb-chain/greenfield-go-sdk/client"
 	"github.com/urfave/cli/v2"
 )
 
+func cmdTaskList() *cli.Command {
+	return &cli.Command{
+		Name:      "ls",
+		Action:    listTasks,
+		Usage:     "list all the tasks",
+		ArgsUsage: "",
+		Description: `
+List the upload and download tasks under the home directory, including the task id, type, bucket, folder, status,
+the number of objects in each status, the transferred bytes and the creation time.
+
+Examples:
+$ gnfd-cmd task ls
+$ gnfd-cmd -o json task ls`,
+	}
+}
+
 func cmdTaskStatus() *cli.Command {
 	return &cli.Command{
 		Name:      "status",
@@ -22,7 +43,7 @@ $ gnfd-cmd task status --taskId 123`,
 			&cli.StringFlag{
 				Name:     taskIDFlag,
 				Value:    "",
-				Usage:    "task id",
+				Usage:    "task id, a unique prefix of the task id is also accepted",
 				Required: true,
 			},
 		},
@@ -42,7 +63,7 @@ $ gnfd-cmd task delete --taskId 123 `,
 			&cli.StringFlag{
 				Name:     taskIDFlag,
 				Value:    "",
-				Usage:    "task id",
+				Usage:    "task id, a unique prefix of the task id is also accepted",
 				Required: true,
 			},
 		},
@@ -57,38 +78,82 @@ func cmdTaskRetry() *cli.Command {
 		ArgsUsage: "",
 		Description: `
 Examples:
-$ gnfd-cmd task retry --taskId 123 `,
+$ gnfd-cmd task retry --taskId 123
+# retry the task with 10 objects uploaded at the same time
+$ gnfd-cmd task retry --taskId 123 --concurrency 10`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     taskIDFlag,
 				Value:    "",
-				Usage:    "task id",
+				Usage:    "task id, a unique prefix of the task id is also accepted",
 				Required: true,
 			},
+			&cli.IntFlag{
+				Name:    concurrencyFlag,
+				Value:   1,
+				Usage:   "indicate the number of objects to be uploaded or downloaded at the same time",
+				EnvVars: []string{concurrencyEnv},
+			},
+			&cli.IntFlag{
+				Name:  batchSizeFlag,
+				Value: defaultCreateBatchSize,
+				Usage: "the max number of objects created on chain in one txn, only used by the upload task",
+			},
+			&cli.DurationFlag{
+				Name:    sealTimeoutFlag,
+				Value:   defaultSealTimeout,
+				Usage:   "the max time to wait for the objects to be sealed after uploading, 0 means no limit, only used by the upload task",
+				EnvVars: []string{sealTimeoutEnv},
+			},
 		},
 	}
 }
 
+// taskStatusRecord is the record of the task queried by "task status", including the states of the objects
+type taskStatusRecord struct {
+	*taskSummary
+	Objects []*UploadTaskObject `json:"objects"`
+}
+
 func getTaskStatus(ctx *cli.Context) error {
 	content, err := getTaskState(ctx)
 	if err != nil {
-		return err
+		return toCmdErr(err)
+	}
+	if isStructuredOutput() {
+		record := taskStatusRecord{taskSummary: newTaskSummary(content), Objects: make([]*UploadTaskObject, 0, len(content.ObjectState))}
+		for index := 0; index < len(content.ObjectState); index++ {
+			if object, ok := content.ObjectState[index]; ok {
+				record.Objects = append(record.Objects, object)
+			}
+		}
+		return printRecord(record)
+	}
+	if content.IsDownload() {
+		fmt.Fprintf(infoWriter, "Download: gnfd://%s/%s\n", content.BucketName, content.Prefix)
 	}
-	fmt.Printf("Folder: %s\n", content.FolderName)
-	fmt.Printf("Status: %s\n", content.Status)
+	fmt.Fprintf(infoWriter, "Folder: %s\n", content.FolderName)
+	fmt.Fprintf(infoWriter, "Status: %s\n", content.Status)
 	for _, state := range content.ObjectState {
-		fmt.Printf("%s\n", fmt.Sprintf("%s %s %s", state.Status, state.ObjectName, state.Comment))
+		if state.CreateTxnHash != "" {
+			fmt.Fprintf(infoWriter, "%s\n", fmt.Sprintf("%s %s %s create txn: %s", state.Status, state.ObjectName, state.Comment, state.CreateTxnHash))
+			continue
+		}
+		fmt.Fprintf(infoWriter, "%s\n", fmt.Sprintf("%s %s %s", state.Status, state.ObjectName, state.Comment))
 	}
-	fmt.Println()
+	fmt.Fprintln(infoWriter)
 	return nil
 }
 
 func deleteTask(ctx *cli.Context) error {
-	taskID := ctx.String(taskIDFlag)
 	homeDir, err := getHomeDir(ctx)
 	if err != nil {
 		return toCmdErr(err)
 	}
+	taskID, err := resolveTaskID(homeDir, ctx.String(taskIDFlag))
+	if err != nil {
+		return toCmdErr(err)
+	}
 	taskFileName := fmt.Sprintf("/.%s", taskID)
 	taskFilePath := filepath.Join(homeDir, taskFileName)
 	if !fileExists(taskFilePath) {
@@ -104,7 +169,7 @@ func deleteTask(ctx *cli.Context) error {
 func retryTask(ctx *cli.Context) error {
 	content, err := getTaskState(ctx)
 	if err != nil {
-		return err
+		return toCmdErr(err)
 	}
 	homeDir, err := getHomeDir(ctx)
 	if err != nil {
@@ -114,36 +179,303 @@ func retryTask(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("task: %s\n", content.TaskID)
-	fmt.Printf("folder name: %s\n", content.FolderName)
-	fmt.Println("retrying...")
-	return uploadFolderByTask(ctx, homeDir, gnfdClient, content)
+	fmt.Fprintf(infoWriter, "task: %s\n", content.TaskID)
+	fmt.Fprintf(infoWriter, "folder name: %s\n", content.FolderName)
+	fmt.Fprintln(infoWriter, "retrying...")
+	if content.IsDownload() {
+		if err = downloadFolderByTask(homeDir, gnfdClient, content, ctx.Int(concurrencyFlag)); err != nil {
+			return toCmdErr(err)
+		}
+		return nil
+	}
+	checkLocalFileChanges(gnfdClient, content)
+	return uploadFolderByTask(ctx, homeDir, gnfdClient, content, ctx.Int(concurrencyFlag), ctx.Int(batchSizeFlag))
 }
 
 func getTaskState(ctx *cli.Context) (*TaskState, error) {
-	taskID := ctx.String(taskIDFlag)
 	homeDir, err := getHomeDir(ctx)
 	if err != nil {
-		return nil, toCmdErr(err)
+		return nil, err
 	}
-	taskFileName := fmt.Sprintf("/.%s/state", taskID)
-	taskFilePath := filepath.Join(homeDir, taskFileName)
+	taskID, err := resolveTaskID(homeDir, ctx.String(taskIDFlag))
+	if err != nil {
+		return nil, err
+	}
+	return loadTaskState(homeDir, taskID)
+}
+
+// loadTaskState read the state of the task from the home directory
+func loadTaskState(homeDir, taskID string) (*TaskState, error) {
+	taskFilePath := filepath.Join(getTaskDir(homeDir, taskID), taskSnapshotFileName)
 	if !fileExists(taskFilePath) {
-		return nil, toCmdErr(fmt.Errorf("task not found"))
+		return nil, fmt.Errorf("task not found")
 	}
 	value, err := readFile(taskFilePath)
 	if err != nil {
-		return nil, toCmdErr(err)
+		return nil, err
 	}
-	var content TaskState
+	content := TaskState{Lock: new(sync.Mutex)}
 	err = json.Unmarshal(value, &content)
 	if err != nil {
-		return nil, toCmdErr(err)
+		return nil, err
+	}
+	// the snapshot is compacted periodically, the later transitions are recorded by the journal
+	if err = replayTaskJournal(getTaskDir(homeDir, taskID), &content); err != nil {
+		return nil, err
+	}
+	// the task created by the former version has no creation time, use the modified time of the state file
+	if content.CreateTime == 0 {
+		if stat, statErr := os.Stat(taskFilePath); statErr == nil {
+			content.CreateTime = stat.ModTime().Unix()
+		}
 	}
 	return &content, nil
 }
 
+// listTaskIDs return the ids of all the tasks under the home directory, the state of each task is stored in .<taskID>/state
+func listTaskIDs(homeDir string) ([]string, error) {
+	entries, err := os.ReadDir(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	taskIDs := make([]string, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if fileExists(filepath.Join(homeDir, entry.Name(), "state")) {
+			taskIDs = append(taskIDs, strings.TrimPrefix(entry.Name(), "."))
+		}
+	}
+	return taskIDs, nil
+}
+
+// resolveTaskID return the full task id by the task id or a unique prefix of it
+func resolveTaskID(homeDir, taskIDPrefix string) (string, error) {
+	if taskIDPrefix == "" {
+		return "", fmt.Errorf("task id should not be empty")
+	}
+	if fileExists(filepath.Join(homeDir, "."+taskIDPrefix, "state")) {
+		return taskIDPrefix, nil
+	}
+
+	taskIDs, err := listTaskIDs(homeDir)
+	if err != nil {
+		return "", err
+	}
+	matched := make([]string, 0)
+	for _, taskID := range taskIDs {
+		if strings.HasPrefix(taskID, taskIDPrefix) {
+			matched = append(matched, taskID)
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return "", fmt.Errorf("task not found")
+	case 1:
+		return matched[0], nil
+	default:
+		return "", fmt.Errorf("the task id prefix %s is ambiguous, it matches the tasks: %s", taskIDPrefix, strings.Join(matched, ", "))
+	}
+}
+
+// taskSummary is the brief info of a task displayed by task ls
+type taskSummary struct {
+	TaskID        string         `json:"task_id"`
+	Type          string         `json:"type"`
+	BucketName    string         `json:"bucket_name"`
+	FolderName    string         `json:"folder_name"`
+	Status        string         `json:"status"`
+	ObjectCounts  map[string]int `json:"object_counts"`
+	TotalBytes    int64          `json:"total_bytes"`
+	FinishedBytes int64          `json:"finished_bytes"`
+	CreateTime    string         `json:"create_time"`
+	createTime    int64
+}
+
+func newTaskSummary(state *TaskState) *taskSummary {
+	summary := &taskSummary{
+		TaskID:       state.TaskID,
+		Type:         TaskTypeUpload,
+		BucketName:   state.BucketName,
+		FolderName:   state.FolderName,
+		Status:       state.Status,
+		ObjectCounts: make(map[string]int),
+		CreateTime:   formatTime(state.CreateTime),
+		createTime:   state.CreateTime,
+	}
+	if isStructuredOutput() {
+		summary.CreateTime = formatRecordTime(state.CreateTime)
+	}
+	if state.IsDownload() {
+		summary.Type = TaskTypeDownload
+	}
+	for _, object := range state.ObjectState {
+		summary.ObjectCounts[object.Status]++
+		summary.TotalBytes += object.ObjectSize
+		// the payload of the object has been transferred if it is uploaded (created or sealed) or downloaded
+		if object.Status == TaskObjectStatusCreated || object.Status == TaskObjectStatusSeal || object.Status == TaskObjectStatusDownloaded {
+			summary.FinishedBytes += object.ObjectSize
+		}
+	}
+	return summary
+}
+
+// objectCountsString format the object counts like "sealed:3 failed:1", the status is sorted by name
+func (s *taskSummary) objectCountsString() string {
+	statuses := make([]string, 0, len(s.ObjectCounts))
+	for status := range s.ObjectCounts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	counts := make([]string, len(statuses))
+	for i, status := range statuses {
+		counts[i] = fmt.Sprintf("%s:%d", status, s.ObjectCounts[status])
+	}
+	return strings.Join(counts, " ")
+}
+
+func listTasks(ctx *cli.Context) error {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return toCmdErr(err)
+	}
+	taskIDs, err := listTaskIDs(homeDir)
+	if err != nil {
+		return toCmdErr(err)
+	}
+
+	summaries := make([]*taskSummary, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		state, loadErr := loadTaskState(homeDir, taskID)
+		if loadErr != nil {
+			fmt.Fprintf(infoWriter, "failed to load task %s: %v\n", taskID, loadErr)
+			continue
+		}
+		summaries = append(summaries, newTaskSummary(state))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].createTime < summaries[j].createTime
+	})
+
+	if isStructuredOutput() {
+		for _, summary := range summaries {
+			if err = printRecord(summary); err != nil {
+				return toCmdErr(err)
+			}
+		}
+		return nil
+	}
+
+	if len(summaries) == 0 {
+		fmt.Fprintln(infoWriter, "no task found")
+		return nil
+	}
+	format := fmt.Sprintf("%%-%ds %%-36s %%-8s %%-10s %%-19s %%s\n", timeColumnWidth())
+	fmt.Fprintf(infoWriter, format, "create time", "task id", "type", "status", "bytes", "bucket / folder")
+	for _, summary := range summaries {
+		fmt.Fprintf(infoWriter, format, summary.CreateTime, summary.TaskID, summary.Type, summary.Status,
+			getConvertSize(summary.FinishedBytes)+"/"+getConvertSize(summary.TotalBytes),
+			summary.BucketName+" / "+summary.FolderName)
+		fmt.Fprintf(infoWriter, "%s objects: %s\n", strings.Repeat(" ", timeColumnWidth()), summary.objectCountsString())
+	}
+	return nil
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path) //os.Stat获取文件信息
 	return !os.IsNotExist(err)
 }
+
+// checkLocalFileChanges check if the local files of the upload task have been changed since the task was built.
+// If the object of the changed file has not been created on chain, the object is re-planned with the new content,
+// otherwise the checksums on chain do not match the new content, and the object is marked as stale.
+func checkLocalFileChanges(gnfdClient client.IClient, taskState *TaskState) {
+	c, cancelCheck := context.WithCancel(globalContext)
+	defer cancelCheck()
+
+	for index := 0; index < len(taskState.ObjectState); index++ {
+		object := taskState.ObjectState[index]
+		// the payload of the created or sealed object has been uploaded, the folder has no local content
+		if object.IsFolder || object.Status == TaskObjectStatusCreated ||
+			object.Status == TaskObjectStatusSeal || object.Status == TaskObjectStatusStale {
+			continue
+		}
+		// the task created by the former version has no fingerprint
+		if object.Fingerprint == "" {
+			continue
+		}
+
+		stat, err := os.Stat(object.FilePath)
+		if err != nil {
+			createdOnChain, checkErr := isObjectCreatedOnChain(c, gnfdClient, object)
+			if checkErr != nil {
+				object.Status = TaskObjectStatusFailed
+				object.Comment = checkErr.Error()
+				continue
+			}
+			if createdOnChain {
+				markObjectStale(object, "the file has been deleted after the object was created on chain")
+			} else {
+				markObjectStale(object, "the file has been deleted")
+			}
+			continue
+		}
+
+		if stat.Size() == object.ObjectSize && stat.ModTime().UnixNano() == object.ModTime {
+			continue
+		}
+		fingerprint, err := computeFileFingerprint(object.FilePath, stat.Size())
+		if err != nil {
+			object.Status = TaskObjectStatusFailed
+			object.Comment = err.Error()
+			continue
+		}
+		if stat.Size() == object.ObjectSize && fingerprint == object.Fingerprint {
+			// only the modified time is changed, the content is treated as the same
+			object.ModTime = stat.ModTime().UnixNano()
+			continue
+		}
+
+		createdOnChain, err := isObjectCreatedOnChain(c, gnfdClient, object)
+		if err != nil {
+			object.Status = TaskObjectStatusFailed
+			object.Comment = err.Error()
+			continue
+		}
+		if createdOnChain {
+			markObjectStale(object, "the file has been changed after the object was created on chain, "+
+				"please delete the object and upload the file again")
+			continue
+		}
+		object.ObjectSize = stat.Size()
+		object.ModTime = stat.ModTime().UnixNano()
+		object.Fingerprint = fingerprint
+		object.Status = TaskObjectStatusWaitForUpload
+		object.Comment = ""
+		fmt.Fprintf(infoWriter, "the file %s has been changed, re-plan the object %s\n", object.FilePath, object.ObjectName)
+	}
+}
+
+// isObjectCreatedOnChain check if the object of the task has been created on chain. The hash of the create txn is
+// also recorded for the failed txns, so the object which is not known to be created is checked by HeadObject.
+func isObjectCreatedOnChain(c context.Context, gnfdClient client.IClient, object *UploadTaskObject) (bool, error) {
+	if object.Status == TaskObjectStatusCreatedOnChain {
+		return true, nil
+	}
+	_, err := gnfdClient.HeadObject(c, object.BucketName, object.ObjectName)
+	if err == nil {
+		return true, nil
+	}
+	if isNoSuchObjectErr(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check if the object has been created on chain: %v", err)
+}
+
+func markObjectStale(object *UploadTaskObject, comment string) {
+	object.Status = TaskObjectStatusStale
+	object.Comment = comment
+	fmt.Fprintf(infoWriter, "%s %s %s\n", TaskObjectStatusStale, object.ObjectName, comment)
+}
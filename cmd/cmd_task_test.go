@@ -0,0 +1,303 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	storageTypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func TestResolveTaskID(t *testing.T) {
+	homeDir := t.TempDir()
+	for _, taskID := range []string{"1a2b3c", "1a2d4e", "5f6a7b", "5f6a7b8c"} {
+		if err := os.MkdirAll(filepath.Join(homeDir, "."+taskID), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(homeDir, "."+taskID, "state"), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the dirs without the state file are not tasks
+	if err := os.MkdirAll(filepath.Join(homeDir, ".9a8b7c"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		prefix  string
+		want    string
+		wantErr string
+	}{
+		{prefix: "1a2b3c", want: "1a2b3c"},
+		{prefix: "1a2b", want: "1a2b3c"},
+		{prefix: "1a2d", want: "1a2d4e"},
+		{prefix: "5f6a7b", want: "5f6a7b"},
+		{prefix: "5f6a7b8", want: "5f6a7b8c"},
+		{prefix: "1a2", wantErr: "ambiguous"},
+		{prefix: "5f", wantErr: "ambiguous"},
+		{prefix: "9a8b", wantErr: "not found"},
+		{prefix: "ffff", wantErr: "not found"},
+		{prefix: "", wantErr: "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got, err := resolveTaskID(homeDir, tt.prefix)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got (%s, %v), expected the error %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadTaskStateOfFormerVersion load the task written by the former version, the folders are still recognized
+func TestLoadTaskStateOfFormerVersion(t *testing.T) {
+	homeDir := t.TempDir()
+	taskDir := getTaskDir(homeDir, "former")
+	if err := os.MkdirAll(taskDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	state := `{"object_state":{"0":{"bucket_name":"bucket","object_name":"dir/","file_path":"dir","upload_single_folder":true,` +
+		`"object_size":0,"status":"sealed","comment":""},"1":{"bucket_name":"bucket","object_name":"dir/a","file_path":"dir/a",` +
+		`"upload_single_folder":false,"object_size":3,"status":"failed","comment":"timeout"}},"task_id":"former","status":"failed",` +
+		`"bucket_name":"bucket","folder_name":"dir","flag":{}}`
+	if err := os.WriteFile(filepath.Join(taskDir, taskSnapshotFileName), []byte(state), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadTaskState(homeDir, "former")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.IsDownload() || !loaded.ObjectState[0].IsFolder || loaded.ObjectState[1].IsFolder {
+		t.Errorf("got the task %+v, expected an upload task with the folder dir/", loaded)
+	}
+	if loaded.CreateTime == 0 {
+		t.Errorf("the create time of the task is not set by the state file")
+	}
+}
+
+func TestComputeFileFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"a": "content", "b": "content", "c": "Content"}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fingerprint := func(name string, size int64) string {
+		result, err := computeFileFingerprint(filepath.Join(dir, name), size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if fingerprint("a", 7) != fingerprint("b", 7) {
+		t.Errorf("got different fingerprints of the same content")
+	}
+	if fingerprint("a", 7) == fingerprint("c", 7) {
+		t.Errorf("got the same fingerprint of a same-size edit")
+	}
+	if fingerprint("a", 7) == fingerprint("a", 8) {
+		t.Errorf("got the same fingerprint of different sizes")
+	}
+	if _, err := computeFileFingerprint(filepath.Join(dir, "missing"), 7); err == nil {
+		t.Errorf("expected an error of the missing file")
+	}
+}
+
+func TestCheckLocalFileChanges(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	tests := []struct {
+		name   string
+		status string
+		// change the local file after the task was built
+		change func(t *testing.T, filePath string)
+		// noFingerprint means the task is built by the former version
+		noFingerprint bool
+		onChain       bool
+		headErr       error
+		wantStatus    string
+		wantReplanned bool
+	}{
+		{name: "unchanged", status: TaskObjectStatusWaitForUpload, wantStatus: TaskObjectStatusWaitForUpload},
+		{
+			name:       "only the modified time is changed",
+			status:     TaskObjectStatusWaitForUpload,
+			change:     func(t *testing.T, filePath string) { touchFile(t, filePath) },
+			wantStatus: TaskObjectStatusWaitForUpload,
+		},
+		{
+			name:          "same-size edit before created",
+			status:        TaskObjectStatusWaitForUpload,
+			change:        func(t *testing.T, filePath string) { writeFile(t, filePath, "Content") },
+			wantStatus:    TaskObjectStatusWaitForUpload,
+			wantReplanned: true,
+		},
+		{
+			name:       "same-size edit after created",
+			status:     TaskObjectStatusCreatedOnChain,
+			change:     func(t *testing.T, filePath string) { writeFile(t, filePath, "Content") },
+			wantStatus: TaskObjectStatusStale,
+		},
+		{
+			name:       "edit of a failed object created on chain",
+			status:     TaskObjectStatusFailed,
+			change:     func(t *testing.T, filePath string) { writeFile(t, filePath, "new content") },
+			onChain:    true,
+			wantStatus: TaskObjectStatusStale,
+		},
+		{
+			name:       "deleted",
+			status:     TaskObjectStatusWaitForUpload,
+			change:     func(t *testing.T, filePath string) { removeFile(t, filePath) },
+			wantStatus: TaskObjectStatusStale,
+		},
+		{
+			name:       "deleted and the chain is not reachable",
+			status:     TaskObjectStatusWaitForUpload,
+			change:     func(t *testing.T, filePath string) { removeFile(t, filePath) },
+			headErr:    errors.New("connection refused"),
+			wantStatus: TaskObjectStatusFailed,
+		},
+		{
+			name:       "sealed",
+			status:     TaskObjectStatusSeal,
+			change:     func(t *testing.T, filePath string) { writeFile(t, filePath, "Content") },
+			wantStatus: TaskObjectStatusSeal,
+		},
+		{
+			name:          "task of the former version",
+			status:        TaskObjectStatusWaitForUpload,
+			change:        func(t *testing.T, filePath string) { writeFile(t, filePath, "Content") },
+			noFingerprint: true,
+			wantStatus:    TaskObjectStatusWaitForUpload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "file")
+			writeFile(t, filePath, "content")
+			stat, err := os.Stat(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fingerprint, err := computeFileFingerprint(filePath, stat.Size())
+			if err != nil {
+				t.Fatal(err)
+			}
+			object := &UploadTaskObject{
+				BucketName: "bucket", ObjectName: "file", FilePath: filePath, ObjectSize: stat.Size(),
+				Status: tt.status, ModTime: stat.ModTime().UnixNano(), Fingerprint: fingerprint,
+			}
+			if tt.noFingerprint {
+				object.Fingerprint = ""
+			}
+			taskState := &TaskState{Lock: new(sync.Mutex), ObjectState: map[int]*UploadTaskObject{0: object}}
+			if tt.change != nil {
+				tt.change(t, filePath)
+			}
+
+			gnfdClient := &fakeGnfdClient{
+				headObject: func(bucketName, objectName string) (*sdktypes.ObjectDetail, error) {
+					if tt.headErr != nil {
+						return nil, tt.headErr
+					}
+					if !tt.onChain {
+						return nil, storageTypes.ErrNoSuchObject
+					}
+					return &sdktypes.ObjectDetail{ObjectInfo: &storageTypes.ObjectInfo{}}, nil
+				},
+			}
+			checkLocalFileChanges(gnfdClient, taskState)
+
+			if object.Status != tt.wantStatus {
+				t.Errorf("got the status %s (%s), expected %s", object.Status, object.Comment, tt.wantStatus)
+			}
+			if replanned := object.Fingerprint != fingerprint && !tt.noFingerprint; replanned != tt.wantReplanned {
+				t.Errorf("got the object re-planned %v, expected %v", replanned, tt.wantReplanned)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, filePath, content string) {
+	if err := os.WriteFile(filePath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// make sure the modified time is changed on the file systems of the coarse time
+	touchFile(t, filePath)
+}
+
+func touchFile(t *testing.T, filePath string) {
+	modTime := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filePath, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func removeFile(t *testing.T, filePath string) {
+	if err := os.Remove(filePath); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestTaskStateConcurrentUpdate update the objects of a task from concurrent uploading routines like the parallel
+// upload of a folder, run it with -race to check the locking of the task state
+func TestTaskStateConcurrentUpdate(t *testing.T) {
+	const objectNum = 100
+	taskState := &TaskState{Lock: new(sync.Mutex), ObjectState: make(map[int]*UploadTaskObject)}
+	for index := 0; index < objectNum; index++ {
+		taskState.ObjectState[index] = &UploadTaskObject{Status: TaskObjectStatusWaitForUpload}
+	}
+
+	var wg sync.WaitGroup
+	for routine := 0; routine < 4; routine++ {
+		wg.Add(1)
+		go func(routine int) {
+			defer wg.Done()
+			for index := routine; index < objectNum; index += 4 {
+				taskState.SetCreateTxnHash(index, "hash")
+				taskState.UpdateObjectState(index, TaskObjectStatusCreated, "")
+				if status := taskState.GetObjectStatus((index + 1) % objectNum); status == "" {
+					t.Errorf("got the empty status of object %d", (index+1)%objectNum)
+				}
+			}
+		}(routine)
+	}
+	wg.Wait()
+	taskState.SetStatus(getUploadTaskStatus(taskState))
+
+	for index := 0; index < objectNum; index++ {
+		object := taskState.ObjectState[index]
+		if object.Status != TaskObjectStatusCreated || object.CreateTxnHash != "hash" {
+			t.Errorf("got the object %d %+v, expected it created with the txn hash", index, object)
+		}
+	}
+	if taskState.Status != TaskStatusFail {
+		t.Errorf("got the task status %s, expected %s as the objects are not sealed", taskState.Status, TaskStatusFail)
+	}
+	if taskState.GetObjectStatus(objectNum) != "" {
+		t.Errorf("got the status of an unknown object")
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// the exit codes of the command, they are documented in the README and should not be changed
+const (
+	exitCodeGeneral      = 1
+	exitCodeUsage        = 2
+	exitCodeAuth         = 3
+	exitCodeNotFound     = 4
+	exitCodeNoBalance    = 5
+	exitCodeTxFailed     = 6
+	exitCodeTimeout      = 7
+	exitCodeNetworkError = 8
+)
+
+var exitCodeKinds = map[int]string{
+	exitCodeGeneral:      "general_error",
+	exitCodeUsage:        "usage_error",
+	exitCodeAuth:         "auth_error",
+	exitCodeNotFound:     "not_found",
+	exitCodeNoBalance:    "insufficient_balance",
+	exitCodeTxFailed:     "tx_failed",
+	exitCodeTimeout:      "timeout",
+	exitCodeNetworkError: "network_error",
+}
+
+// noBalanceErrRegexp matches the error of the operator account which has never received any token, so it is not on chain
+var noBalanceErrRegexp = regexp.MustCompile(`account (0x)?[0-9a-fA-F]{40} not found`)
+
+// the keywords of the error message to classify the errors returned by the sdk and the chain, they are only the
+// fallback of the errors which are not returned as CmdError by the commands, and are matched in the order of exitCodeRules.
+// The words which also appear in the other kinds of errors like "keystore" are not keywords, the commands return
+// CmdError for the errors of the keystore.
+var exitCodeRules = []struct {
+	code     int
+	keywords []string
+}{
+	{exitCodeNoBalance, []string{"insufficient funds", "insufficient balance", "insufficient fee"}},
+	{exitCodeNetworkError, []string{"connection refused", "no such host", "dial tcp", "connection reset", "network is unreachable",
+		"code = unavailable", "i/o timeout", "broken pipe", "tls handshake"}},
+	{exitCodeTimeout, []string{"deadline exceeded", "timed out", "timeout", "please check it later", "not sealed"}},
+	{exitCodeAuth, []string{"password", "passphrase", "private key", "decrypt", "access denied", "unauthorized"}},
+	{exitCodeNotFound, []string{"not exist", "not found", "no such", "does not exist"}},
+	{exitCodeTxFailed, []string{"response code", "failed to broadcast", "out of gas"}},
+	{exitCodeUsage, []string{"args number", "the args should", "required flag", "flag provided but not defined", "invalid format",
+		"invalid visibility", "invalid action", "invalid amount", "is not valid amount", "should be set", "can not be used with",
+		"allowed values are", "fail to parse"}},
+}
+
+// CmdError is the error returned by the command actions, the code is used as the exit code of the process
+type CmdError struct {
+	Code int
+	Err  error
+}
+
+func (e *CmdError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CmdError) Unwrap() error {
+	return e.Err
+}
+
+// Kind return the readable type of the error
+func (e *CmdError) Kind() string {
+	if kind, ok := exitCodeKinds[e.Code]; ok {
+		return kind
+	}
+	return exitCodeKinds[exitCodeGeneral]
+}
+
+func newCmdError(code int, err error) *CmdError {
+	return &CmdError{Code: code, Err: err}
+}
+
+// classifyCmdErr wrap the error into CmdError with the exit code decided by the type and message of the error,
+// nil is returned if the error is nil
+func classifyCmdErr(err error) *CmdError {
+	if err == nil {
+		return nil
+	}
+	var cmdErr *CmdError
+	if errors.As(err, &cmdErr) {
+		return cmdErr
+	}
+
+	switch {
+	case errors.Is(err, ErrBucketNotExist), errors.Is(err, ErrObjectNotExist), errors.Is(err, ErrGroupNotExist),
+		errors.Is(err, ErrFileNotExist):
+		return newCmdError(exitCodeNotFound, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return newCmdError(exitCodeTimeout, err)
+	case noBalanceErrRegexp.MatchString(err.Error()):
+		return newCmdError(exitCodeNoBalance, err)
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, rule := range exitCodeRules {
+		for _, keyword := range rule.keywords {
+			if strings.Contains(msg, keyword) {
+				return newCmdError(rule.code, err)
+			}
+		}
+	}
+	return newCmdError(exitCodeGeneral, err)
+}
+
+// printCmdErr print the error to the writer in plain text or json, and return the exit code of the error
+func printCmdErr(w io.Writer, err error, format string) int {
+	cmdErr := classifyCmdErr(err)
+	if format == jsonFormat {
+		content, marshalErr := json.Marshal(struct {
+			Code    int    `json:"code"`
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		}{cmdErr.Code, cmdErr.Kind(), cmdErr.Error()})
+		if marshalErr == nil {
+			fmt.Fprintln(w, string(content))
+			return cmdErr.Code
+		}
+	}
+
+	if cmdErr.Code == exitCodeNoBalance && noBalanceErrRegexp.MatchString(cmdErr.Error()) {
+		fmt.Fprintln(w, "The operator account have no balance, please transfer token to your account")
+	} else {
+		fmt.Fprintf(w, "run command error: %s\n", cmdErr.Error())
+	}
+	return cmdErr.Code
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyCmdErr(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "command error", err: newCmdError(exitCodeAuth, errors.New("object not found")), code: exitCodeAuth},
+		{name: "wrapped command error", err: fmt.Errorf("failed: %w", newCmdError(exitCodeTxFailed, errors.New("failed"))), code: exitCodeTxFailed},
+		{name: "object not exist", err: fmt.Errorf("head: %w", ErrObjectNotExist), code: exitCodeNotFound},
+		{name: "deadline exceeded", err: fmt.Errorf("wait: %w", context.DeadlineExceeded), code: exitCodeTimeout},
+		{
+			name: "account not on chain",
+			err:  errors.New("rpc error: code = NotFound desc = account 0x411674B7187873BF5FB85a012FC8D7069c596801 not found: key not found"),
+			code: exitCodeNoBalance,
+		},
+		{name: "other key not found", err: errors.New("failed to query the policy: key not found"), code: exitCodeNotFound},
+		{name: "insufficient funds", err: errors.New("spendable balance is smaller than fee: insufficient funds"), code: exitCodeNoBalance},
+		{name: "connection refused", err: errors.New("dial tcp 127.0.0.1:26750: connect: connection refused"), code: exitCodeNetworkError},
+		{name: "missing keystore", err: errors.New("the keystore key.json does not exist"), code: exitCodeNotFound},
+		{name: "keystore not exists", err: errors.New("keystore not exists"), code: exitCodeNotFound},
+		{name: "remove keystore", err: errors.New("failed to remove the keystore key.json: operation not permitted"), code: exitCodeGeneral},
+		{name: "write permission", err: errors.New("open task/state: permission denied"), code: exitCodeGeneral},
+		{name: "wrong password", err: errors.New("could not decrypt key with given password"), code: exitCodeAuth},
+		{name: "bucket not found", err: errors.New("rpc error: code = Unknown desc = No such bucket: unknown request"), code: exitCodeNotFound},
+		{name: "tx failed", err: errors.New("the tx has failed with response code: 5, codespace:sdk"), code: exitCodeTxFailed},
+		{name: "txn in message", err: errors.New("the txn hash list is empty"), code: exitCodeGeneral},
+		{name: "usage", err: errors.New("args number error"), code: exitCodeUsage},
+		{name: "unknown", err: errors.New("something went wrong"), code: exitCodeGeneral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmdErr := classifyCmdErr(tt.err)
+			if cmdErr.Code != tt.code {
+				t.Errorf("got the exit code %d (%s), expected %d", cmdErr.Code, cmdErr.Kind(), tt.code)
+			}
+			if cmdErr.Error() != tt.err.Error() && !errors.As(tt.err, new(*CmdError)) {
+				t.Errorf("got the message %q, expected %q", cmdErr.Error(), tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestClassifyNilErr(t *testing.T) {
+	if cmdErr := classifyCmdErr(nil); cmdErr != nil {
+		t.Errorf("got %v, expected nil", cmdErr)
+	}
+	if err := toCmdErr(nil); err != nil {
+		t.Errorf("got %v, expected nil", err)
+	}
+}
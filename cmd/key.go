@@ -3,6 +3,8 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -59,3 +61,55 @@ func decryptKey(key *encryptedKey, auth string) (keyBytes []byte, err error) {
 	}
 	return plainText, nil
 }
+
+const (
+	// lightKdf uses the light scrypt parameters, which take much less time and memory to decrypt the key,
+	// it is suitable for the CI runners but is weaker against brute force
+	lightKdf    = "light"
+	standardKdf = "standard"
+)
+
+// getScryptParams return the scrypt parameters of the kdf
+func getScryptParams(kdf string) (int, int) {
+	if kdf == lightKdf {
+		return keystore.LightScryptN, keystore.LightScryptP
+	}
+	return EncryptScryptN, EncryptScryptP
+}
+
+// getKeyScryptParams return the scrypt parameters of the encrypted key, the standard parameters are returned
+// if they are not found
+func getKeyScryptParams(key *encryptedKey) (int, int) {
+	n, okN := key.Crypto.KDFParams["n"].(float64)
+	p, okP := key.Crypto.KDFParams["p"].(float64)
+	if key.Crypto.KDF != "scrypt" || !okN || !okP {
+		return EncryptScryptN, EncryptScryptP
+	}
+	return int(n), int(p)
+}
+
+// writeKeyFileAtomic write the keystore to a temp file in the same directory and rename it to the keystore file,
+// so that the keystore file is either the old one or the new one if the process crashes
+func writeKeyFileAtomic(keyFilePath string, content []byte) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(keyFilePath), "."+filepath.Base(keyFilePath)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	if _, err = tempFile.Write(content); err == nil {
+		err = tempFile.Sync()
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempPath, 0600)
+	}
+	if err == nil {
+		err = os.Rename(tempPath, keyFilePath)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+	}
+	return err
+}
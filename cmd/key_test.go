@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/urfave/cli/v2"
+)
+
+func TestGetScryptParams(t *testing.T) {
+	tests := []struct {
+		kdf   string
+		wantN int
+		wantP int
+	}{
+		{kdf: lightKdf, wantN: keystore.LightScryptN, wantP: keystore.LightScryptP},
+		{kdf: standardKdf, wantN: EncryptScryptN, wantP: EncryptScryptP},
+		{kdf: "", wantN: EncryptScryptN, wantP: EncryptScryptP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kdf, func(t *testing.T) {
+			if n, p := getScryptParams(tt.kdf); n != tt.wantN || p != tt.wantP {
+				t.Errorf("got (%d, %d), expected (%d, %d)", n, p, tt.wantN, tt.wantP)
+			}
+		})
+	}
+}
+
+func TestGetKeyScryptParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		kdf       string
+		kdfParams map[string]interface{}
+		wantN     int
+		wantP     int
+	}{
+		{name: "light", kdf: "scrypt", kdfParams: map[string]interface{}{"n": float64(4096), "p": float64(6)}, wantN: 4096, wantP: 6},
+		{name: "custom", kdf: "scrypt", kdfParams: map[string]interface{}{"n": float64(8192), "p": float64(1)}, wantN: 8192, wantP: 1},
+		{name: "pbkdf2", kdf: "pbkdf2", kdfParams: map[string]interface{}{"c": float64(262144)}, wantN: EncryptScryptN, wantP: EncryptScryptP},
+		{name: "missing p", kdf: "scrypt", kdfParams: map[string]interface{}{"n": float64(4096)}, wantN: EncryptScryptN, wantP: EncryptScryptP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &encryptedKey{}
+			key.Crypto.KDF = tt.kdf
+			key.Crypto.KDFParams = tt.kdfParams
+			if n, p := getKeyScryptParams(key); n != tt.wantN || p != tt.wantP {
+				t.Errorf("got (%d, %d), expected (%d, %d)", n, p, tt.wantN, tt.wantP)
+			}
+		})
+	}
+}
+
+func TestWriteKeyFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	keyFilePath := filepath.Join(dir, "key.json")
+	if err := os.WriteFile(keyFilePath, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeKeyFileAtomic(keyFilePath, []byte("new")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(keyFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "new" {
+		t.Errorf("got the content %q, expected %q", content, "new")
+	}
+	if stat, _ := os.Stat(keyFilePath); stat.Mode().Perm() != 0600 {
+		t.Errorf("got the mode %s, expected 0600", stat.Mode().Perm())
+	}
+
+	// the rename fails as the keystore path is a directory which is not empty
+	dirPath := filepath.Join(dir, "dir.json")
+	if err = os.MkdirAll(filepath.Join(dirPath, "child"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err = writeKeyFileAtomic(dirPath, []byte("new")); err == nil {
+		t.Fatal("expected an error of writing to a directory")
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if strings.Contains(file.Name(), ".tmp") {
+			t.Errorf("the temp file %s is left after the failed write", file.Name())
+		}
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	privateKey := testAgentPrivateKey
+	address := sdk.AccAddress([]byte("01234567890123456789"))
+
+	tests := []struct {
+		name  string
+		args  []string
+		wantN int
+		wantP int
+	}{
+		{name: "keep the parameters", wantN: 8192, wantP: 1},
+		{name: "light kdf", args: []string{"--kdf", lightKdf}, wantN: keystore.LightScryptN, wantP: keystore.LightScryptP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			keyFilePath := filepath.Join(homeDir, "key.json")
+			keyJson, err := EncryptKey(&Key{Address: address, PrivateKey: privateKey}, "old-password", 8192, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(keyFilePath, keyJson, 0600); err != nil {
+				t.Fatal(err)
+			}
+			oldPasswordFile, newPasswordFile := filepath.Join(homeDir, "old.txt"), filepath.Join(homeDir, "new.txt")
+			if err = os.WriteFile(oldPasswordFile, []byte("old-password\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(newPasswordFile, []byte("new-password\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			app := &cli.App{
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: homeFlag},
+					&cli.StringFlag{Name: keyStoreFlag},
+					&cli.StringFlag{Name: passwordFileFlag},
+				},
+				Commands:       []*cli.Command{cmdChangePassword()},
+				ExitErrHandler: func(_ *cli.Context, _ error) {},
+			}
+			args := append([]string{"gnfd-cmd", "--home", homeDir, "--keystore", keyFilePath, "--passwordfile", oldPasswordFile,
+				"passwd", "--newPasswordFile", newPasswordFile}, tt.args...)
+			if err = app.Run(args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			newKeyJson, err := os.ReadFile(keyFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decrypted, err := DecryptKey(newKeyJson, "new-password"); err != nil || decrypted != privateKey {
+				t.Errorf("failed to decrypt the key with the new password: %v", err)
+			}
+			if _, err = DecryptKey(newKeyJson, "old-password"); err == nil {
+				t.Errorf("the key is decrypted with the old password")
+			}
+			encrypted := new(encryptedKey)
+			if err = json.Unmarshal(newKeyJson, encrypted); err != nil {
+				t.Fatal(err)
+			}
+			if n, p := getKeyScryptParams(encrypted); n != tt.wantN || p != tt.wantP {
+				t.Errorf("got the scrypt parameters (%d, %d), expected (%d, %d)", n, p, tt.wantN, tt.wantP)
+			}
+			if stat, _ := os.Stat(keyFilePath); stat.Mode().Perm() != 0600 {
+				t.Errorf("got the mode %s, expected 0600", stat.Mode().Perm())
+			}
+		})
+	}
+}
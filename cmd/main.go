@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 
@@ -23,49 +22,98 @@ func main() {
 	if err != nil {
 		homeDir, err = os.Getwd()
 		if err != nil {
-			fmt.Println("fail to get home dir or local dir")
+			fmt.Fprintln(infoWriter, "fail to get home dir or local dir")
 		}
 	}
 
 	flags := []cli.Flag{
-		altsrc.NewStringFlag(
-			&cli.StringFlag{
-				Name:  "host",
-				Usage: "host name of request",
-			},
-		),
-		altsrc.NewStringFlag(
-			&cli.StringFlag{
-				Name:  "rpcAddr",
-				Usage: "greenfield chain client rpc address",
-			},
-		),
-		altsrc.NewStringFlag(
-			&cli.StringFlag{
-				Name:  "chainId",
-				Usage: "greenfield chainId",
-			},
-		),
+		// the network settings of the config file are resolved by getConfig with the profiles
+		&cli.StringFlag{
+			Name:    "host",
+			Usage:   "host name of request",
+			EnvVars: []string{hostEnv},
+		},
+		&cli.StringFlag{
+			Name:    "rpcAddr",
+			Usage:   "greenfield chain client rpc address",
+			EnvVars: []string{rpcAddrEnv},
+		},
+		&cli.StringFlag{
+			Name:    "chainId",
+			Usage:   "greenfield chainId",
+			EnvVars: []string{chainIdEnv},
+		},
+		&cli.StringFlag{
+			Name:    profileFlag,
+			Usage:   "name of the network profile defined in the config file or the built-in presets of mainnet, testnet and local",
+			EnvVars: []string{profileEnv},
+		},
 
 		&cli.StringFlag{
 			Name:    passwordFileFlag,
 			Aliases: []string{"p"},
 			Usage:   "password file for encrypting and decoding the private key",
+			EnvVars: []string{passwordFileEnv},
 		},
 		&cli.StringFlag{
 			Name:    configFlag,
 			Aliases: []string{"c"},
 			Usage:   "Load configuration from `FILE`",
+			EnvVars: []string{configEnv},
 		},
 		&cli.StringFlag{
 			Name:    keyStoreFlag,
 			Aliases: []string{"k"},
-			Usage:   "keystore file path",
+			Usage:   "keystore file path, or the name or the address of an account in the home dir",
+			EnvVars: []string{keyStoreEnv},
+		},
+		altsrc.NewStringFlag(
+			&cli.StringFlag{
+				Name:    limitRateFlag,
+				Usage:   "limit the total transfer rate of uploading and downloading, such as 20MB/s, 512KB/s or 1048576 (bytes per second)",
+				EnvVars: []string{limitRateEnv},
+			},
+		),
+		altsrc.NewStringFlag(
+			&cli.StringFlag{
+				Name:    timezoneFlag,
+				Usage:   "timezone of the printed and entered times, it can be local, UTC or an IANA name like Europe/Berlin",
+				Value:   defaultTimezone,
+				EnvVars: []string{timezoneEnv},
+			},
+		),
+		altsrc.NewStringFlag(
+			&cli.StringFlag{
+				Name:    timeFormatFlag,
+				Usage:   "format of the printed and entered times, it can be iso8601, rfc3339 or a go time layout like \"02 Jan 06 15:04 MST\"",
+				Value:   iso8601TimeFormat,
+				EnvVars: []string{timeFormatEnv},
+			},
+		),
+		&cli.GenericFlag{
+			Name:    outputFlag,
+			Aliases: []string{"o"},
+			Value: &CmdEnumValue{
+				Enum:    []string{tableOutput, jsonOutput, yamlOutput},
+				Default: tableOutput,
+			},
+			Usage:   "set format of the command results of table, json or yaml, the items of the lists are printed as json lines or yaml documents",
+			EnvVars: []string{outputEnv},
+		},
+		&cli.GenericFlag{
+			Name: errorFormatFlag,
+			Value: &CmdEnumValue{
+				Enum:    []string{defaultFormat, jsonFormat},
+				Default: defaultFormat,
+			},
+			Usage:   "set format of the error printed to stderr of plaintxt or json",
+			EnvVars: []string{errorFormatEnv},
 		},
 		&cli.StringFlag{
-			Name:  homeFlag,
-			Usage: "directory for config and keystore",
-			Value: filepath.Join(homeDir, DefaultConfigDir),
+			Name:    homeFlag,
+			Usage:   "directory for config and keystore",
+			Value:   filepath.Join(homeDir, DefaultConfigDir),
+			EnvVars: []string{homeEnv},
 		},
 	}
 
@@ -95,11 +143,13 @@ func main() {
 				Subcommands: []*cli.Command{
 					cmdPutObj(),
 					cmdGetObj(),
+					cmdSyncObjects(),
 					cmdDelObject(),
 					cmdHeadObj(),
 					cmdCancelObjects(),
 					cmdListObjects(),
 					cmdCalHash(),
+					cmdVerifyObject(),
 					cmdUpdateObject(),
 					cmdGetUploadProgress(),
 					cmdMirrorObject(),
@@ -171,25 +221,82 @@ func main() {
 					cmdCreateAccount(),
 					cmdExportAccount(),
 					cmdSetDefaultAccount(),
+					cmdChangePassword(),
+					cmdRenameAccount(),
+					cmdRemoveAccount(),
+				},
+			},
+			{
+				Name:  "agent",
+				Usage: "support the agent holding the decrypted key, including start/stop/status",
+				Subcommands: []*cli.Command{
+					cmdStartAgent(),
+					cmdStopAgent(),
+					cmdAgentStatus(),
 				},
 			},
 			{
 				Name:  "task",
-				Usage: "support the batch upload file",
+				Usage: "support the batch upload and download tasks",
 				Subcommands: []*cli.Command{
+					cmdTaskList(),
 					cmdTaskStatus(),
 					cmdTaskDelete(),
 					cmdTaskRetry(),
 				},
 			},
+			{
+				Name:  "config",
+				Usage: "support the config operation functions, including init/show/get/set/validate and the network profiles",
+				Subcommands: []*cli.Command{
+					cmdInitConfig(),
+					cmdShowConfig(),
+					cmdGetConfig(),
+					cmdSetConfig(),
+					cmdValidateConfig(),
+					cmdUseProfile(),
+				},
+			},
 			cmdShowVersion(),
 		},
 	}
-	app.Before = altsrc.InitInputSourceWithContext(flags, altsrc.NewTomlSourceFromFlagFunc("config"))
+	initInputSource := altsrc.InitInputSourceWithContext(flags, altsrc.NewTomlSourceFromFlagFunc("config"))
+	app.Before = func(ctx *cli.Context) error {
+		errorFormat = ctx.String(errorFormatFlag)
+		setOutputFormat(ctx.String(outputFlag))
+		if err := initInputSource(ctx); err != nil {
+			return err
+		}
+		if err := initTimeFormat(ctx.String(timezoneFlag), ctx.String(timeFormatFlag)); err != nil {
+			return newCmdError(exitCodeUsage, err)
+		}
+		return initRateLimiter(ctx.String(limitRateFlag))
+	}
+
+	// the errors are printed by main with the exit codes instead of by the cli lib
+	app.ExitErrHandler = func(ctx *cli.Context, err error) {}
+	app.OnUsageError = onUsageError
+	setUsageErrorHandler(app.Commands)
 
 	err = app.Run(os.Args)
 	if err != nil {
-		log.Fatal(err)
+		os.Exit(printCmdErr(os.Stderr, err, errorFormat))
 	}
+}
+
+// errorFormat is the format of the error printed before exiting, it is set by the errorFormat flag
+var errorFormat = defaultFormat
+
+func onUsageError(ctx *cli.Context, err error, isSubcommand bool) error {
+	return newCmdError(exitCodeUsage, err)
+}
 
+// setUsageErrorHandler make the errors of parsing the flags of the commands return the usage exit code
+func setUsageErrorHandler(commands []*cli.Command) {
+	for _, command := range commands {
+		if command.OnUsageError == nil {
+			command.OnUsageError = onUsageError
+		}
+		setUsageErrorHandler(command.Subcommands)
+	}
 }
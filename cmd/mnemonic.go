@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/eth/ethsecp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/go-bip39"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+const (
+	// defaultHDPathPrefix is the BIP-44 path of the ethereum accounts without the address index
+	defaultHDPathPrefix = "m/44'/60'/0'/0/"
+	defaultHDPath       = defaultHDPathPrefix + "0"
+	// mnemonicEntropyBits is the entropy of the new mnemonics, which have 24 words
+	mnemonicEntropyBits = 256
+)
+
+// newMnemonic generate a new BIP-39 mnemonic
+func newMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// deriveKeyFromMnemonic derive the ethsecp256k1 key from the mnemonic by the BIP-32 derivation path
+func deriveKeyFromMnemonic(mnemonic, hdPath string) (*Key, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	// IsMnemonicValid only checks the words, MnemonicToByteArray also checks the checksum
+	if _, err := bip39.MnemonicToByteArray(mnemonic); err != nil {
+		return nil, errors.New("invalid mnemonic, please check the words and the order of them")
+	}
+	seed := bip39.NewSeed(mnemonic, "")
+	masterPriv, chainCode := hd.ComputeMastersFromSeed(seed)
+	derivedPriv, err := hd.DerivePrivateKeyForPath(masterPriv, chainCode, strings.TrimPrefix(hdPath, "m/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path %s: %v", hdPath, err)
+	}
+	priKey := hd.EthSecp256k1.Generate()(derivedPriv).(*ethsecp256k1.PrivKey)
+	return &Key{
+		Address:    sdk.AccAddress(priKey.PubKey().Address()),
+		PrivateKey: hex.EncodeToString(priKey.Bytes()),
+	}, nil
+}
+
+// getHDPath return the derivation path set by the hdPath flag or the accountIndex flag
+func getHDPath(ctx *cli.Context) (string, error) {
+	if ctx.IsSet(accountIndexFlag) {
+		if ctx.IsSet(hdPathFlag) {
+			return "", fmt.Errorf("the flag %s can not be used with %s", accountIndexFlag, hdPathFlag)
+		}
+		return defaultHDPathPrefix + strconv.FormatUint(uint64(ctx.Uint(accountIndexFlag)), 10), nil
+	}
+	return ctx.String(hdPathFlag), nil
+}
+
+// loadKeyFromMnemonic derive the key from the mnemonic read from the file of the first arg or the terminal
+func loadKeyFromMnemonic(ctx *cli.Context) (*Key, error) {
+	hdPath, err := getHDPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mnemonic string
+	if mnemonicFile := ctx.Args().First(); mnemonicFile != "" {
+		content, err := os.ReadFile(mnemonicFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the mnemonic file: %v", err)
+		}
+		mnemonic = string(content)
+	} else {
+		// the mnemonic is not echoed, so that it will not be left on the screen
+		fmt.Fprint(os.Stderr, "Please enter the mnemonic now:")
+		content, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the mnemonic: %v", err)
+		}
+		mnemonic = string(content)
+	}
+	return deriveKeyFromMnemonic(mnemonic, hdPath)
+}
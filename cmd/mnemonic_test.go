@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestDeriveKeyFromMnemonic(t *testing.T) {
+	tests := []struct {
+		name       string
+		mnemonic   string
+		hdPath     string
+		address    string
+		privateKey string
+		wantErr    bool
+	}{
+		{
+			name:       "first account",
+			mnemonic:   testMnemonic,
+			hdPath:     defaultHDPath,
+			address:    "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+			privateKey: "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+		},
+		{
+			name:     "second account",
+			mnemonic: testMnemonic,
+			hdPath:   defaultHDPathPrefix + "1",
+			address:  "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		},
+		{
+			name:     "bip-39 vector",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			hdPath:   defaultHDPath,
+			address:  "0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+		},
+		{
+			name:     "extra whitespaces",
+			mnemonic: "  test test test test test test\ntest test test test test   junk\n",
+			hdPath:   defaultHDPath,
+			address:  "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		},
+		{
+			name:     "invalid checksum",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			hdPath:   defaultHDPath,
+			wantErr:  true,
+		},
+		{
+			name:     "unknown word",
+			mnemonic: "test test test test test test test test test test test gnfd",
+			hdPath:   defaultHDPath,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid path",
+			mnemonic: testMnemonic,
+			hdPath:   "m/44'/60'/x",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := deriveKeyFromMnemonic(tt.mnemonic, tt.hdPath)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got the address %s", key.Address.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key.Address.String() != tt.address {
+				t.Errorf("got the address %s, expected %s", key.Address.String(), tt.address)
+			}
+			if tt.privateKey != "" && key.PrivateKey != tt.privateKey {
+				t.Errorf("got the private key %s, expected %s", key.PrivateKey, tt.privateKey)
+			}
+		})
+	}
+}
+
+func TestGetHDPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		hdPath  string
+		wantErr bool
+	}{
+		{name: "default", hdPath: defaultHDPath},
+		{name: "hd path", flags: map[string]string{hdPathFlag: "m/44'/60'/1'/0/2"}, hdPath: "m/44'/60'/1'/0/2"},
+		{name: "account index", flags: map[string]string{accountIndexFlag: "3"}, hdPath: defaultHDPathPrefix + "3"},
+		{name: "both", flags: map[string]string{accountIndexFlag: "3", hdPathFlag: defaultHDPath}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := flag.NewFlagSet(tt.name, flag.ContinueOnError)
+			set.String(hdPathFlag, defaultHDPath, "")
+			set.Uint(accountIndexFlag, 0, "")
+			for name, value := range tt.flags {
+				if err := set.Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			hdPath, err := getHDPath(cli.NewContext(cli.NewApp(), set, nil))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got the path %s", hdPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hdPath != tt.hdPath {
+				t.Errorf("got the path %s, expected %s", hdPath, tt.hdPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cosmos/gogoproto/proto"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	tableOutput = "table"
+	jsonOutput  = "json"
+	yamlOutput  = "yaml"
+)
+
+var (
+	// outputFormat is the format of the command results set by the global output flag.
+	// The results are printed as hand-formatted text in table mode, and as records in json or yaml mode.
+	outputFormat = tableOutput
+	// resultWriter is the writer of the records and the downloaded data, it is always the stdout
+	resultWriter io.Writer = os.Stdout
+	// infoWriter is the writer of the informational messages and the progress, it is the stdout in table mode and
+	// the stderr in json or yaml mode, so that the stdout only contains the records
+	infoWriter io.Writer = os.Stdout
+)
+
+// setOutputFormat set the output format and the writer of the informational messages
+func setOutputFormat(format string) {
+	outputFormat = format
+	if isStructuredOutput() {
+		infoWriter = os.Stderr
+	}
+}
+
+// isStructuredOutput return true if the results should be printed as json or yaml records
+func isStructuredOutput() bool {
+	return outputFormat == jsonOutput || outputFormat == yamlOutput
+}
+
+// printRecord print a record of the result, every json record is printed in one line and every yaml record
+// is printed as a yaml document, so the items of the lists can be streamed one by one.
+// The proto messages of the chain are marshaled with the original field names.
+func printRecord(record interface{}) error {
+	var (
+		content []byte
+		err     error
+	)
+	if msg, ok := record.(proto.Message); ok {
+		var jsonData string
+		jsonData, err = getJsonMarshaler().MarshalToString(msg)
+		content = []byte(jsonData)
+	} else {
+		content, err = json.Marshal(record)
+	}
+	if err != nil {
+		return err
+	}
+	if outputFormat == yamlOutput {
+		if content, err = yaml.JSONToYAML(content); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(resultWriter, "---\n%s", content)
+		return err
+	}
+	_, err = fmt.Fprintln(resultWriter, string(content))
+	return err
+}
+
+// txnRecord is the record of the commands which send a txn to the chain
+type txnRecord struct {
+	Action     string `json:"action"`
+	Resource   string `json:"resource,omitempty"`
+	ResourceID string `json:"resource_id,omitempty"`
+	Amount     string `json:"amount,omitempty"`
+	TxnHash    string `json:"txn_hash"`
+	Error      string `json:"error,omitempty"`
+}
+
+// downloadRecord is the record of the objects downloaded by "object get"
+type downloadRecord struct {
+	BucketName string `json:"bucket_name"`
+	ObjectName string `json:"object_name"`
+	FilePath   string `json:"file_path"`
+	Size       int64  `json:"size,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
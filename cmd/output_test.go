@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	storageTypes "github.com/bnb-chain/greenfield/x/storage/types"
+	"sigs.k8s.io/yaml"
+)
+
+// captureRecords print the records in the output format and return the printed content
+func captureRecords(t *testing.T, format string, records ...interface{}) string {
+	oldFormat, oldResultWriter, oldInfoWriter := outputFormat, resultWriter, infoWriter
+	defer func() { outputFormat, resultWriter, infoWriter = oldFormat, oldResultWriter, oldInfoWriter }()
+
+	var buf bytes.Buffer
+	outputFormat = format
+	resultWriter = &buf
+	for _, record := range records {
+		if err := printRecord(record); err != nil {
+			t.Fatalf("failed to print the record: %v", err)
+		}
+	}
+	return buf.String()
+}
+
+func TestPrintRecordJson(t *testing.T) {
+	records := []interface{}{
+		txnRecord{Action: "make_group", Resource: "group1", TxnHash: "hash1"},
+		downloadRecord{BucketName: "bucket", ObjectName: "dir/object", FilePath: "/tmp/object", Size: 10},
+	}
+	lines := strings.Split(strings.TrimSuffix(captureRecords(t, jsonOutput, records...), "\n"), "\n")
+	if len(lines) != len(records) {
+		t.Fatalf("got %d lines, expected %d", len(lines), len(records))
+	}
+
+	var txn txnRecord
+	if err := json.Unmarshal([]byte(lines[0]), &txn); err != nil {
+		t.Fatalf("failed to parse the line %q: %v", lines[0], err)
+	}
+	if txn != records[0] {
+		t.Errorf("got %+v, expected %+v", txn, records[0])
+	}
+	var download downloadRecord
+	if err := json.Unmarshal([]byte(lines[1]), &download); err != nil {
+		t.Fatalf("failed to parse the line %q: %v", lines[1], err)
+	}
+	if download != records[1] {
+		t.Errorf("got %+v, expected %+v", download, records[1])
+	}
+}
+
+func TestPrintRecordYaml(t *testing.T) {
+	records := []interface{}{
+		txnRecord{Action: "make_group", Resource: "group1", TxnHash: "hash1"},
+		txnRecord{Action: "update_group", Resource: "group1", TxnHash: "hash2"},
+	}
+	content := captureRecords(t, yamlOutput, records...)
+	if !strings.HasPrefix(content, "---\n") {
+		t.Fatalf("got %q, expected to start with the document separator", content)
+	}
+	docs := strings.Split(strings.TrimPrefix(content, "---\n"), "---\n")
+	if len(docs) != len(records) {
+		t.Fatalf("got %d documents, expected %d", len(docs), len(records))
+	}
+	for i, doc := range docs {
+		var txn txnRecord
+		if err := yaml.Unmarshal([]byte(doc), &txn); err != nil {
+			t.Fatalf("failed to parse the document %q: %v", doc, err)
+		}
+		if txn != records[i] {
+			t.Errorf("got %+v, expected %+v", txn, records[i])
+		}
+	}
+}
+
+func TestPrintRecordProto(t *testing.T) {
+	msg := &storageTypes.MsgDeleteBucket{Operator: "0x75345BC9FfFAe09486dE7EC954bAfAEcE29b9b24", BucketName: "bucket"}
+	tests := []struct {
+		name   string
+		format string
+	}{
+		{name: "json", format: jsonOutput},
+		{name: "yaml", format: yamlOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := captureRecords(t, tt.format, msg)
+			if tt.format == jsonOutput && strings.Count(content, "\n") != 1 {
+				t.Errorf("got %q, expected one line", content)
+			}
+			fields := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(strings.TrimPrefix(content, "---\n")), &fields); err != nil {
+				t.Fatalf("failed to parse %q: %v", content, err)
+			}
+			if fields["bucket_name"] != msg.BucketName {
+				t.Errorf("got %v, expected the bucket_name %s", fields, msg.BucketName)
+			}
+			if _, ok := fields["bucketName"]; ok {
+				t.Errorf("got the camel case field in %v, expected the original field names", fields)
+			}
+		})
+	}
+}
+
+func TestSetOutputFormat(t *testing.T) {
+	oldFormat, oldInfoWriter := outputFormat, infoWriter
+	defer func() { outputFormat, infoWriter = oldFormat, oldInfoWriter }()
+
+	tests := []struct {
+		format     string
+		structured bool
+		infoWriter *os.File
+	}{
+		{format: tableOutput, structured: false, infoWriter: os.Stdout},
+		{format: jsonOutput, structured: true, infoWriter: os.Stderr},
+		{format: yamlOutput, structured: true, infoWriter: os.Stderr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			infoWriter = os.Stdout
+			setOutputFormat(tt.format)
+			if isStructuredOutput() != tt.structured {
+				t.Errorf("got structured %v, expected %v", isStructuredOutput(), tt.structured)
+			}
+			if infoWriter != tt.infoWriter {
+				t.Errorf("got the info writer %v, expected %v", infoWriter, tt.infoWriter)
+			}
+		})
+	}
+}
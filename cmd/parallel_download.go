@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/greenfield-go-sdk/client"
+	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+)
+
+// downloadCheckpoint records the finished parts of the parallel download, so that the download can be resumed
+type downloadCheckpoint struct {
+	BucketName    string `json:"bucket_name"`
+	ObjectName    string `json:"object_name"`
+	ObjectID      string `json:"object_id"`
+	ObjectSize    int64  `json:"object_size"`
+	PartSize      int64  `json:"part_size"`
+	FinishedParts []bool `json:"finished_parts"`
+}
+
+// isMatched check if the checkpoint is created by the download of the same object with the same part size
+func (cp *downloadCheckpoint) isMatched(bucketName, objectName, objectID string, objectSize, partSize int64) bool {
+	return cp.BucketName == bucketName && cp.ObjectName == objectName && cp.ObjectID == objectID &&
+		cp.ObjectSize == objectSize && cp.PartSize == partSize && int64(len(cp.FinishedParts)) == getPartsNum(objectSize, partSize)
+}
+
+// save write the checkpoint to a temp file and rename it, so that the checkpoint file is never half written
+func (cp *downloadCheckpoint) save(checkpointPath string) error {
+	content, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tempPath := checkpointPath + ".tmp"
+	if err = os.WriteFile(tempPath, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, checkpointPath)
+}
+
+// loadDownloadCheckpoint load the checkpoint of the former download, nil is returned if it does not exist
+func loadDownloadCheckpoint(checkpointPath string) *downloadCheckpoint {
+	content, err := os.ReadFile(checkpointPath)
+	if err != nil {
+		return nil
+	}
+	cp := &downloadCheckpoint{}
+	if err = json.Unmarshal(content, cp); err != nil {
+		return nil
+	}
+	return cp
+}
+
+// loadResumableCheckpoint return the checkpoint of the former download if it can be resumed, which requires the
+// checkpoint is of the same object and the temp file still has the preallocated size. Otherwise the finished parts
+// of the checkpoint may be lost, nil is returned to start a new download.
+func loadResumableCheckpoint(checkpointPath, tempFilePath, bucketName, objectName, objectID string, objectSize, partSize int64) *downloadCheckpoint {
+	cp := loadDownloadCheckpoint(checkpointPath)
+	if cp == nil || !cp.isMatched(bucketName, objectName, objectID, objectSize, partSize) {
+		return nil
+	}
+	stat, err := os.Stat(tempFilePath)
+	if err != nil || !stat.Mode().IsRegular() || stat.Size() != objectSize {
+		return nil
+	}
+	return cp
+}
+
+func getPartsNum(objectSize, partSize int64) int64 {
+	return (objectSize + partSize - 1) / partSize
+}
+
+// downloadObjectInParallel download the disjoint ranges of the object at the same time and write them at their
+// offsets of a preallocated temp file. The finished parts are recorded in a checkpoint file, if the download
+// is interrupted, run it again with the same part size to resume it.
+func downloadObjectInParallel(c context.Context, gnfdClient client.IClient, bucketName, objectName, filePath string,
+	concurrency int, partSize int64, verify bool) error {
+	if partSize <= 0 {
+		return errors.New("the part size should be more than 0")
+	}
+
+	objectDetail, err := gnfdClient.HeadObject(c, bucketName, objectName)
+	if err != nil {
+		return headObjectErr(bucketName, objectName, err)
+	}
+	objectSize := int64(objectDetail.ObjectInfo.PayloadSize)
+	objectID := objectDetail.ObjectInfo.Id.String()
+
+	tempFilePath := filepath.Join(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	checkpointPath := tempFilePath + ".checkpoint"
+
+	cp := loadResumableCheckpoint(checkpointPath, tempFilePath, bucketName, objectName, objectID, objectSize, partSize)
+	if cp == nil {
+		// the former download is not the same object or its temp file is lost, start a new download
+		cp = &downloadCheckpoint{
+			BucketName:    bucketName,
+			ObjectName:    objectName,
+			ObjectID:      objectID,
+			ObjectSize:    objectSize,
+			PartSize:      partSize,
+			FinishedParts: make([]bool, getPartsNum(objectSize, partSize)),
+		}
+		if err = os.Remove(tempFilePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		fmt.Fprintln(infoWriter, "resume the download from the checkpoint", checkpointPath)
+	}
+
+	fd, err := os.OpenFile(tempFilePath, os.O_CREATE|os.O_WRONLY, 0660)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	// preallocate the temp file so that the parts can be written at their offsets
+	if err = fd.Truncate(objectSize); err != nil {
+		return err
+	}
+	if err = cp.save(checkpointPath); err != nil {
+		return err
+	}
+
+	var (
+		lock      sync.Mutex
+		failedNum int
+		lastErr   error
+	)
+	progress := &ProgressWriter{
+		Writer:      io.Discard,
+		Total:       objectSize,
+		StartTime:   time.Now(),
+		LastPrinted: time.Now(),
+	}
+	for partIndex, finished := range cp.FinishedParts {
+		if finished {
+			progress.Current += partLength(int64(partIndex), partSize, objectSize)
+		}
+	}
+
+	pool := NewPool(concurrency)
+	for partIndex, finished := range cp.FinishedParts {
+		if finished {
+			continue
+		}
+		pool.Add(1)
+		go func(partIndex int64) {
+			defer pool.Done()
+			downloadErr := downloadObjectPart(c, gnfdClient, bucketName, objectName, fd, partIndex, partSize, objectSize, &lock, progress)
+			if downloadErr == nil {
+				// the part should be on the disk before the checkpoint records it as finished
+				downloadErr = fd.Sync()
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			if downloadErr != nil {
+				failedNum++
+				lastErr = downloadErr
+				return
+			}
+			cp.FinishedParts[partIndex] = true
+			if saveErr := cp.save(checkpointPath); saveErr != nil {
+				failedNum++
+				lastErr = saveErr
+			}
+		}(int64(partIndex))
+	}
+	pool.Wait()
+
+	if failedNum > 0 {
+		return fmt.Errorf("%d parts failed to be downloaded, run the command again to resume the download, the last error: %v", failedNum, lastErr)
+	}
+
+	if err = fd.Sync(); err != nil {
+		return err
+	}
+	if err = fd.Close(); err != nil {
+		return err
+	}
+
+	if verify {
+		if err = verifyFileWithObject(c, gnfdClient, bucketName, objectName, tempFilePath); err != nil {
+			os.Remove(tempFilePath)
+			os.Remove(checkpointPath)
+			return err
+		}
+	}
+
+	if err = os.Rename(tempFilePath, filePath); err != nil {
+		return err
+	}
+	os.Remove(checkpointPath)
+	if isStructuredOutput() {
+		return printRecord(downloadRecord{BucketName: bucketName, ObjectName: objectName, FilePath: filePath, Size: objectSize})
+	}
+	fmt.Fprintf(infoWriter, "\ndownload object %s, the file path is %s, content length:%d \n", objectName, filePath, objectSize)
+	return nil
+}
+
+func partLength(partIndex, partSize, objectSize int64) int64 {
+	start := partIndex * partSize
+	if start+partSize > objectSize {
+		return objectSize - start
+	}
+	return partSize
+}
+
+// downloadObjectPart download the range of the part and write it at the offset of the part
+func downloadObjectPart(c context.Context, gnfdClient client.IClient, bucketName, objectName string, fd *os.File,
+	partIndex, partSize, objectSize int64, progressLock *sync.Mutex, progress *ProgressWriter) error {
+	start := partIndex * partSize
+	length := partLength(partIndex, partSize, objectSize)
+
+	opt := sdktypes.GetObjectOptions{}
+	if err := opt.SetRange(start, start+length-1); err != nil {
+		return err
+	}
+
+	body, _, err := gnfdClient.GetObject(c, bucketName, objectName, opt)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	writer := &partWriter{
+		Writer:   newRateLimitedWriter(io.NewOffsetWriter(fd, start)),
+		lock:     progressLock,
+		progress: progress,
+	}
+	written, err := io.Copy(writer, io.LimitReader(body, length))
+	if err != nil {
+		return err
+	}
+	if written != length {
+		return fmt.Errorf("the length of part %d is %d, expected %d", partIndex, written, length)
+	}
+	return nil
+}
+
+// partWriter write the data of a part and update the progress shared by all the parts
+type partWriter struct {
+	io.Writer
+	lock     *sync.Mutex
+	progress *ProgressWriter
+}
+
+func (w *partWriter) Write(p []byte) (int, error) {
+	// the inner writer waits for the rate limiter before holding the lock, so that the parts are not serialized by the lock
+	n, err := w.Writer.Write(p)
+	w.lock.Lock()
+	w.progress.Current += int64(n)
+	w.progress.printProgress()
+	w.lock.Unlock()
+	return n, err
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"cosmossdk.io/math"
+	sdktypes "github.com/bnb-chain/greenfield-go-sdk/types"
+	storageTypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func TestDownloadCheckpointIsMatched(t *testing.T) {
+	cp := &downloadCheckpoint{
+		BucketName:    "bucket",
+		ObjectName:    "object",
+		ObjectID:      "1",
+		ObjectSize:    10,
+		PartSize:      4,
+		FinishedParts: []bool{true, false, false},
+	}
+
+	tests := []struct {
+		name       string
+		bucketName string
+		objectName string
+		objectID   string
+		objectSize int64
+		partSize   int64
+		cp         *downloadCheckpoint
+		want       bool
+	}{
+		{name: "same object", bucketName: "bucket", objectName: "object", objectID: "1", objectSize: 10, partSize: 4, cp: cp, want: true},
+		{name: "another bucket", bucketName: "other", objectName: "object", objectID: "1", objectSize: 10, partSize: 4, cp: cp},
+		{name: "another object", bucketName: "bucket", objectName: "other", objectID: "1", objectSize: 10, partSize: 4, cp: cp},
+		{name: "recreated object", bucketName: "bucket", objectName: "object", objectID: "2", objectSize: 10, partSize: 4, cp: cp},
+		{name: "another size", bucketName: "bucket", objectName: "object", objectID: "1", objectSize: 12, partSize: 4, cp: cp},
+		{name: "another part size", bucketName: "bucket", objectName: "object", objectID: "1", objectSize: 10, partSize: 5, cp: cp},
+		{
+			name:       "broken parts",
+			bucketName: "bucket", objectName: "object", objectID: "1", objectSize: 10, partSize: 4,
+			cp: &downloadCheckpoint{BucketName: "bucket", ObjectName: "object", ObjectID: "1", ObjectSize: 10, PartSize: 4,
+				FinishedParts: []bool{true, false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cp.isMatched(tt.bucketName, tt.objectName, tt.objectID, tt.objectSize, tt.partSize); got != tt.want {
+				t.Errorf("got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadCheckpointSaveAndLoad(t *testing.T) {
+	checkpointPath := filepath.Join(t.TempDir(), ".object.tmp.checkpoint")
+	if cp := loadDownloadCheckpoint(checkpointPath); cp != nil {
+		t.Fatalf("got the checkpoint %+v of a missing file", cp)
+	}
+
+	cp := &downloadCheckpoint{BucketName: "bucket", ObjectName: "object", ObjectID: "1", ObjectSize: 10, PartSize: 4,
+		FinishedParts: []bool{true, false, true}}
+	if err := cp.save(checkpointPath); err != nil {
+		t.Fatal(err)
+	}
+	if loaded := loadDownloadCheckpoint(checkpointPath); !reflect.DeepEqual(loaded, cp) {
+		t.Errorf("got the checkpoint %+v, expected %+v", loaded, cp)
+	}
+	if _, err := os.Stat(checkpointPath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("the temp checkpoint is left: %v", err)
+	}
+
+	if err := os.WriteFile(checkpointPath, []byte(`{"bucket_name":"buck`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if loaded := loadDownloadCheckpoint(checkpointPath); loaded != nil {
+		t.Errorf("got the checkpoint %+v of a broken file", loaded)
+	}
+}
+
+func TestPartLength(t *testing.T) {
+	tests := []struct {
+		partIndex, partSize, objectSize, want int64
+	}{
+		{partIndex: 0, partSize: 4, objectSize: 10, want: 4},
+		{partIndex: 1, partSize: 4, objectSize: 10, want: 4},
+		{partIndex: 2, partSize: 4, objectSize: 10, want: 2},
+		{partIndex: 0, partSize: 4, objectSize: 3, want: 3},
+		{partIndex: 1, partSize: 5, objectSize: 10, want: 5},
+	}
+
+	for _, tt := range tests {
+		if got := partLength(tt.partIndex, tt.partSize, tt.objectSize); got != tt.want {
+			t.Errorf("got the length %d of part %d, expected %d", got, tt.partIndex, tt.want)
+		}
+		if parts := getPartsNum(tt.objectSize, tt.partSize); tt.partIndex >= parts {
+			t.Errorf("part %d is out of the %d parts", tt.partIndex, parts)
+		}
+	}
+}
+
+func TestLoadResumableCheckpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		// tempSize is the size of the temp file, -1 means the temp file does not exist
+		tempSize int64
+		objectID string
+		want     bool
+	}{
+		{name: "intact temp file", tempSize: 10, objectID: "1", want: true},
+		{name: "missing temp file", tempSize: -1, objectID: "1"},
+		{name: "truncated temp file", tempSize: 4, objectID: "1"},
+		{name: "another object", tempSize: 10, objectID: "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFilePath := filepath.Join(t.TempDir(), ".object.tmp")
+			checkpointPath := tempFilePath + ".checkpoint"
+			cp := &downloadCheckpoint{BucketName: "bucket", ObjectName: "object", ObjectID: "1", ObjectSize: 10, PartSize: 4,
+				FinishedParts: []bool{true, false, true}}
+			if err := cp.save(checkpointPath); err != nil {
+				t.Fatal(err)
+			}
+			if tt.tempSize >= 0 {
+				if err := os.WriteFile(tempFilePath, make([]byte, tt.tempSize), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := loadResumableCheckpoint(checkpointPath, tempFilePath, "bucket", "object", tt.objectID, 10, 4)
+			if (got != nil) != tt.want {
+				t.Errorf("got the checkpoint %+v, expected resumable %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDownloadObjectInParallelResume resume the download whose first part has been finished, the finished part is
+// downloaded again only if the temp file is lost
+func TestDownloadObjectInParallelResume(t *testing.T) {
+	oldInfoWriter := infoWriter
+	defer func() { infoWriter = oldInfoWriter }()
+	infoWriter = io.Discard
+
+	content := []byte("0123456789")
+	const partSize = 4
+
+	tests := []struct {
+		name string
+		// prepare change the temp file of the interrupted download
+		prepare   func(t *testing.T, tempFilePath string)
+		wantParts []string
+	}{
+		{name: "intact temp file", wantParts: []string{"bytes=4-7", "bytes=8-9"}},
+		{
+			name:      "deleted temp file",
+			prepare:   func(t *testing.T, tempFilePath string) { removeFile(t, tempFilePath) },
+			wantParts: []string{"bytes=0-3", "bytes=4-7", "bytes=8-9"},
+		},
+		{
+			name: "truncated temp file",
+			prepare: func(t *testing.T, tempFilePath string) {
+				if err := os.Truncate(tempFilePath, 2); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantParts: []string{"bytes=0-3", "bytes=4-7", "bytes=8-9"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "object")
+			tempFilePath := filepath.Join(filepath.Dir(filePath), ".object.tmp")
+			// the first part has been downloaded by the interrupted download
+			tempContent := make([]byte, len(content))
+			copy(tempContent, content[:partSize])
+			if err := os.WriteFile(tempFilePath, tempContent, 0600); err != nil {
+				t.Fatal(err)
+			}
+			cp := &downloadCheckpoint{BucketName: "bucket", ObjectName: "object", ObjectID: "1", ObjectSize: int64(len(content)),
+				PartSize: partSize, FinishedParts: []bool{true, false, false}}
+			if err := cp.save(tempFilePath + ".checkpoint"); err != nil {
+				t.Fatal(err)
+			}
+			if tt.prepare != nil {
+				tt.prepare(t, tempFilePath)
+			}
+
+			var (
+				lock  sync.Mutex
+				parts = make(map[string]bool)
+			)
+			gnfdClient := &fakeGnfdClient{
+				headObject: func(bucketName, objectName string) (*sdktypes.ObjectDetail, error) {
+					return &sdktypes.ObjectDetail{ObjectInfo: &storageTypes.ObjectInfo{Id: math.NewUint(1), PayloadSize: uint64(len(content))}}, nil
+				},
+				getObject: func(bucketName, objectName string, opts sdktypes.GetObjectOptions) (io.ReadCloser, error) {
+					var start, end int
+					if _, err := fmt.Sscanf(opts.Range, "bytes=%d-%d", &start, &end); err != nil {
+						return nil, err
+					}
+					lock.Lock()
+					parts[opts.Range] = true
+					lock.Unlock()
+					return io.NopCloser(bytes.NewReader(content[start : end+1])), nil
+				},
+			}
+
+			if err := downloadObjectInParallel(globalContext, gnfdClient, "bucket", "object", filePath, 2, partSize, false); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			downloaded, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(downloaded, content) {
+				t.Errorf("got the content %q, expected %q", downloaded, content)
+			}
+			if len(parts) != len(tt.wantParts) {
+				t.Errorf("got the parts %v, expected %v", parts, tt.wantParts)
+			}
+			for _, part := range tt.wantParts {
+				if !parts[part] {
+					t.Errorf("the part %s is not downloaded", part)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// globalRateLimiter limits the total bandwidth of all the transfers of the command, nil means no limit
+var globalRateLimiter *rateLimiter
+
+var rateRegexp = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([kmg]?)(i?b)?(/s)?$`)
+
+// rateLimiter is a token bucket shared by the concurrent transfers, the tokens are bytes
+type rateLimiter struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(bytesPerSecond float64) *rateLimiter {
+	return &rateLimiter{
+		rate:   bytesPerSecond,
+		burst:  bytesPerSecond,
+		tokens: bytesPerSecond,
+		last:   time.Now(),
+	}
+}
+
+// wait take n tokens from the bucket and sleep until the tokens are refilled if the bucket is in debt
+func (l *rateLimiter) wait(n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	l.lock.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.lock.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// parseRate parse the rate like 20MB/s, 512K or 1048576 to bytes per second, the units are based on 1024
+func parseRate(rate string) (float64, error) {
+	matches := rateRegexp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(rate)))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid rate %s, the rate should be like 20MB/s, 512KB/s or 1048576", rate)
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	switch matches[2] {
+	case "k":
+		value *= 1 << 10
+	case "m":
+		value *= 1 << 20
+	case "g":
+		value *= 1 << 30
+	}
+	if value < 1 {
+		return 0, fmt.Errorf("invalid rate %s, the rate should be at least 1 byte per second", rate)
+	}
+	return value, nil
+}
+
+// initRateLimiter set the global rate limiter by the limit-rate flag
+func initRateLimiter(rate string) error {
+	if rate == "" {
+		return nil
+	}
+	bytesPerSecond, err := parseRate(rate)
+	if err != nil {
+		return err
+	}
+	globalRateLimiter = newRateLimiter(bytesPerSecond)
+	return nil
+}
+
+// rateLimitedReader limit the reading rate by the global rate limiter
+type rateLimitedReader struct {
+	io.Reader
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	globalRateLimiter.wait(n)
+	return n, err
+}
+
+// rateLimitedWriter limit the writing rate by the global rate limiter
+type rateLimitedWriter struct {
+	io.Writer
+}
+
+func (w *rateLimitedWriter) Write(p []byte) (int, error) {
+	globalRateLimiter.wait(len(p))
+	return w.Writer.Write(p)
+}
+
+// newRateLimitedReader wrap the reader with the global rate limiter if the rate is limited
+func newRateLimitedReader(reader io.Reader) io.Reader {
+	if globalRateLimiter == nil {
+		return reader
+	}
+	return &rateLimitedReader{Reader: reader}
+}
+
+// newRateLimitedWriter wrap the writer with the global rate limiter if the rate is limited
+func newRateLimitedWriter(writer io.Writer) io.Writer {
+	if globalRateLimiter == nil {
+		return writer
+	}
+	return &rateLimitedWriter{Writer: writer}
+}
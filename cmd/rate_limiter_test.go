@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		rate    string
+		want    float64
+		wantErr bool
+	}{
+		{rate: "1048576", want: 1048576},
+		{rate: "512K", want: 512 << 10},
+		{rate: "512KB/s", want: 512 << 10},
+		{rate: "20MB/s", want: 20 << 20},
+		{rate: "20mib/s", want: 20 << 20},
+		{rate: "1.5M", want: 1.5 * (1 << 20)},
+		{rate: "2G", want: 2 << 30},
+		{rate: " 100b/s ", want: 100},
+		{rate: "", wantErr: true},
+		{rate: "0", wantErr: true},
+		{rate: "0.5", wantErr: true},
+		{rate: "-1M", wantErr: true},
+		{rate: "10TB", wantErr: true},
+		{rate: "fast", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rate, func(t *testing.T) {
+			got, err := parseRate(tt.rate)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %f", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %f, expected %f", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRateLimitWithProgress copy the data through the same chain of the progress and the limiter as the commands,
+// the limit should be applied once: the first second is the burst of the bucket, the rest is transferred at the rate
+func TestRateLimitWithProgress(t *testing.T) {
+	const rate = 256 << 10
+	data := bytes.Repeat([]byte("a"), 3*rate)
+
+	oldLimiter, oldInfoWriter := globalRateLimiter, infoWriter
+	defer func() { globalRateLimiter, infoWriter = oldLimiter, oldInfoWriter }()
+	infoWriter = io.Discard
+
+	tests := []struct {
+		name string
+		copy func() (int64, error)
+	}{
+		{
+			name: "upload",
+			copy: func() (int64, error) {
+				reader := &ProgressReader{Reader: newRateLimitedReader(bytes.NewReader(data)), Total: int64(len(data)), StartTime: time.Now()}
+				return io.Copy(io.Discard, reader)
+			},
+		},
+		{
+			name: "download",
+			copy: func() (int64, error) {
+				writer := &ProgressWriter{Writer: newRateLimitedWriter(io.Discard), Total: int64(len(data)), StartTime: time.Now()}
+				return io.Copy(writer, bytes.NewReader(data))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			globalRateLimiter = newRateLimiter(rate)
+			start := time.Now()
+			written, err := tt.copy()
+			elapsed := time.Since(start)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if written != int64(len(data)) {
+				t.Fatalf("got %d bytes, expected %d", written, len(data))
+			}
+			if elapsed < 1500*time.Millisecond || elapsed > 3*time.Second {
+				t.Errorf("the transfer took %s, expected about 2s", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+const (
+	taskSnapshotFileName = "state"
+	taskJournalFileName  = "journal"
+	// compact the journal into the snapshot after the number of records has been appended
+	taskJournalCompactRecords = 1000
+	// taskJournalIndexOfTask is the index of the journal record which updates the status of the whole task
+	taskJournalIndexOfTask = -1
+)
+
+// taskJournalRecord is a state transition of the task, the mutable fields of the object after the transition are recorded
+type taskJournalRecord struct {
+	Seq           uint64 `json:"seq"`
+	Index         int    `json:"index"`
+	Status        string `json:"status"`
+	Comment       string `json:"comment,omitempty"`
+	CreateTxnHash string `json:"create_txn_hash,omitempty"`
+}
+
+// taskJournal is the append-only file of the state transitions of the task
+type taskJournal struct {
+	taskDir string
+	file    *os.File
+	records int
+	// syncLock serializes the syncs and the compactions of the journal, syncedSeq is the seq of the last record
+	// synced to the disk
+	syncLock  sync.Mutex
+	syncedSeq uint64
+	// compacting is set once the journal is full, the records appended while the snapshot is written are kept
+	// in pending to be rewritten to the truncated journal
+	compacting bool
+	pending    [][]byte
+}
+
+func getTaskDir(homeDir, taskID string) string {
+	return filepath.Join(homeDir, "."+taskID)
+}
+
+// openJournal open the journal of the task and write a snapshot of the current state,
+// the later state transitions are appended to the journal until closeJournal is called
+func (t *TaskState) openJournal(homeDir string) error {
+	taskDir := getTaskDir(homeDir, t.TaskID)
+	if err := os.MkdirAll(taskDir, 0700); err != nil {
+		return fmt.Errorf("failed to create task directory %s: %v", taskDir, err)
+	}
+	file, err := os.OpenFile(filepath.Join(taskDir, taskJournalFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	t.journal = &taskJournal{taskDir: taskDir, file: file}
+	return t.compactLocked()
+}
+
+// closeJournal compact the journal into the snapshot and close it
+func (t *TaskState) closeJournal() error {
+	t.Lock.Lock()
+	journal := t.journal
+	t.Lock.Unlock()
+	if journal == nil {
+		return nil
+	}
+
+	// wait for the compaction in progress, or its older snapshot may replace the final one
+	journal.syncLock.Lock()
+	defer journal.syncLock.Unlock()
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	if t.journal != journal {
+		return nil
+	}
+	err := t.compactLocked()
+	if closeErr := t.journal.file.Close(); err == nil {
+		err = closeErr
+	}
+	t.journal = nil
+	return err
+}
+
+// appendJournalLocked append the transition of the object (or the task if index is taskJournalIndexOfTask)
+// to the journal and return the seq of the record, 0 is returned if nothing is appended. The caller must hold
+// the lock, and call syncJournal with the seq after releasing the lock to confirm the transition, the full
+// journal is also compacted by syncJournal.
+func (t *TaskState) appendJournalLocked(index int) uint64 {
+	if t.journal == nil {
+		return 0
+	}
+	record := taskJournalRecord{Seq: t.JournalSeq + 1, Index: index}
+	if index == taskJournalIndexOfTask {
+		record.Status = t.Status
+	} else if object, ok := t.ObjectState[index]; ok {
+		record.Status = object.Status
+		record.Comment = object.Comment
+		record.CreateTxnHash = object.CreateTxnHash
+	} else {
+		return 0
+	}
+
+	content, err := json.Marshal(record)
+	if err != nil {
+		return 0
+	}
+	content = append(content, '\n')
+	if _, err = t.journal.file.Write(content); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write the task journal: %v\n", err)
+		return 0
+	}
+	t.JournalSeq = record.Seq
+	t.journal.records++
+	if t.journal.compacting {
+		t.journal.pending = append(t.journal.pending, content)
+	} else if t.journal.records >= taskJournalCompactRecords {
+		t.journal.compacting = true
+	}
+	return record.Seq
+}
+
+// syncJournal wait until the journal record of the seq has been synced to the disk. The lock of the state is not
+// held while syncing, and the records appended by the concurrent routines in the meantime are synced together,
+// so one sync confirms the transitions of many objects.
+func (t *TaskState) syncJournal(seq uint64) {
+	if seq == 0 {
+		return
+	}
+	t.Lock.Lock()
+	journal := t.journal
+	t.Lock.Unlock()
+	if journal == nil {
+		return
+	}
+
+	journal.syncLock.Lock()
+	defer journal.syncLock.Unlock()
+	if err := t.compactJournal(journal); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to compact the task journal: %v\n", err)
+	}
+	if journal.syncedSeq >= seq {
+		return
+	}
+	// all the records appended before the sync are synced by it
+	t.Lock.Lock()
+	appendedSeq := t.JournalSeq
+	t.Lock.Unlock()
+	if err := journal.file.Sync(); err != nil {
+		// the journal closed by closeJournal has been compacted into the snapshot
+		if !errors.Is(err, os.ErrClosed) {
+			fmt.Fprintf(os.Stderr, "failed to sync the task journal: %v\n", err)
+		}
+		return
+	}
+	journal.syncedSeq = appendedSeq
+}
+
+// compactJournal compact the journal once it is full. Like the syncs of the journal, the state is marshaled under
+// the lock but the snapshot is written, synced and renamed without holding it, so the concurrent transitions are
+// not blocked. The records appended in the meantime are rewritten to the truncated journal, none of them has been
+// confirmed as the caller must hold the sync lock, and they are synced by the following sync.
+func (t *TaskState) compactJournal(journal *taskJournal) error {
+	t.Lock.Lock()
+	if t.journal != journal || !journal.compacting {
+		t.Lock.Unlock()
+		return nil
+	}
+	content, err := json.Marshal(t)
+	snapshotSeq := t.JournalSeq
+	journal.pending = nil
+	t.Lock.Unlock()
+
+	if err == nil {
+		err = writeTaskSnapshot(journal.taskDir, content)
+	}
+
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	pending := journal.pending
+	journal.compacting = false
+	journal.pending = nil
+	if err != nil {
+		return err
+	}
+	if err = journal.file.Truncate(0); err != nil {
+		return err
+	}
+	journal.records = 0
+	for _, content = range pending {
+		if _, err = journal.file.Write(content); err != nil {
+			return err
+		}
+		journal.records++
+	}
+	// the records compacted into the snapshot are durable
+	if journal.syncedSeq < snapshotSeq {
+		journal.syncedSeq = snapshotSeq
+	}
+	return nil
+}
+
+// compactLocked write the snapshot of the state and truncate the journal. The caller must hold the lock.
+func (t *TaskState) compactLocked() error {
+	content, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if err = writeTaskSnapshot(t.journal.taskDir, content); err != nil {
+		return err
+	}
+
+	if err = t.journal.file.Truncate(0); err != nil {
+		return err
+	}
+	t.journal.records = 0
+	t.journal.compacting = false
+	t.journal.pending = nil
+	return nil
+}
+
+// writeTaskSnapshot write the snapshot to a temp file and rename it to the snapshot file, the journal is only
+// truncated after it returns. The records of the journal are applied only if their seq is larger than the seq
+// of the snapshot, so a crash between renaming and truncating is harmless.
+func writeTaskSnapshot(taskDir string, content []byte) error {
+	snapshotPath := filepath.Join(taskDir, taskSnapshotFileName)
+	tempPath := snapshotPath + ".tmp"
+	tempFile, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = tempFile.Write(content); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err = tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err = tempFile.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tempPath, snapshotPath); err != nil {
+		return err
+	}
+	// the journal is only truncated after the renaming is durable
+	return syncDir(taskDir)
+}
+
+// syncDir sync the dir so that the renaming of the files in it is durable, the dirs can not be synced on windows
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// replayTaskJournal apply the records of the journal whose seq is larger than the seq of the snapshot.
+// The last record may be partially written if the process crashed, the replay stops at the broken record.
+func replayTaskJournal(taskDir string, state *TaskState) error {
+	file, err := os.Open(filepath.Join(taskDir, taskJournalFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record taskJournalRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			break
+		}
+		if record.Seq <= state.JournalSeq {
+			continue
+		}
+		if record.Index == taskJournalIndexOfTask {
+			state.Status = record.Status
+		} else if object, ok := state.ObjectState[record.Index]; ok {
+			object.Status = record.Status
+			object.Comment = record.Comment
+			object.CreateTxnHash = record.CreateTxnHash
+		}
+		state.JournalSeq = record.Seq
+	}
+	return nil
+}
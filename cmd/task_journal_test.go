@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func newTestTaskState(taskID string) *TaskState {
+	return &TaskState{
+		Lock:   new(sync.Mutex),
+		TaskID: taskID,
+		Status: TaskStatusCreate,
+		ObjectState: map[int]*UploadTaskObject{
+			0: {ObjectName: "a", Status: TaskObjectStatusWaitForUpload},
+			1: {ObjectName: "b", Status: TaskObjectStatusWaitForUpload},
+		},
+	}
+}
+
+func TestReplayTaskJournal(t *testing.T) {
+	tests := []struct {
+		name       string
+		snapshot   uint64
+		journal    string
+		wantStatus [2]string
+		wantTxn    string
+		wantTask   string
+		wantSeq    uint64
+	}{
+		{
+			name:       "no journal",
+			wantStatus: [2]string{TaskObjectStatusWaitForUpload, TaskObjectStatusWaitForUpload},
+			wantTask:   TaskStatusCreate,
+		},
+		{
+			name: "apply the records",
+			journal: `{"seq":1,"index":0,"status":"created_on_chain","create_txn_hash":"txn-1"}
+{"seq":2,"index":1,"status":"failed","comment":"failed to upload"}
+{"seq":3,"index":0,"status":"created","create_txn_hash":"txn-1"}
+{"seq":4,"index":-1,"status":"failed"}
+`,
+			wantStatus: [2]string{TaskObjectStatusCreated, TaskObjectStatusFailed},
+			wantTxn:    "txn-1",
+			wantTask:   TaskStatusFail,
+			wantSeq:    4,
+		},
+		{
+			name:     "skip the records compacted into the snapshot",
+			snapshot: 2,
+			journal: `{"seq":1,"index":0,"status":"failed"}
+{"seq":2,"index":1,"status":"failed"}
+{"seq":3,"index":1,"status":"created"}
+`,
+			wantStatus: [2]string{TaskObjectStatusWaitForUpload, TaskObjectStatusCreated},
+			wantTask:   TaskStatusCreate,
+			wantSeq:    3,
+		},
+		{
+			name: "stop at the broken record",
+			journal: `{"seq":1,"index":0,"status":"created"}
+{"seq":2,"index":1,"stat`,
+			wantStatus: [2]string{TaskObjectStatusCreated, TaskObjectStatusWaitForUpload},
+			wantTask:   TaskStatusCreate,
+			wantSeq:    1,
+		},
+		{
+			name: "ignore the unknown object",
+			journal: `{"seq":1,"index":5,"status":"created"}
+{"seq":2,"index":1,"status":"sealed"}
+`,
+			wantStatus: [2]string{TaskObjectStatusWaitForUpload, TaskObjectStatusSeal},
+			wantTask:   TaskStatusCreate,
+			wantSeq:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskDir := t.TempDir()
+			if tt.journal != "" {
+				if err := os.WriteFile(filepath.Join(taskDir, taskJournalFileName), []byte(tt.journal), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			state := newTestTaskState("task")
+			state.JournalSeq = tt.snapshot
+			if err := replayTaskJournal(taskDir, state); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			gotStatus := [2]string{state.ObjectState[0].Status, state.ObjectState[1].Status}
+			if gotStatus != tt.wantStatus || state.Status != tt.wantTask || state.JournalSeq != tt.wantSeq {
+				t.Errorf("got the objects %v, the task %s and the seq %d, expected %v, %s and %d",
+					gotStatus, state.Status, state.JournalSeq, tt.wantStatus, tt.wantTask, tt.wantSeq)
+			}
+			if state.ObjectState[0].CreateTxnHash != tt.wantTxn {
+				t.Errorf("got the txn hash %q, expected %q", state.ObjectState[0].CreateTxnHash, tt.wantTxn)
+			}
+		})
+	}
+}
+
+func TestTaskJournalCompaction(t *testing.T) {
+	homeDir := t.TempDir()
+	state := newTestTaskState("task")
+	if err := state.openJournal(homeDir); err != nil {
+		t.Fatal(err)
+	}
+	taskDir := getTaskDir(homeDir, state.TaskID)
+	journalPath := filepath.Join(taskDir, taskJournalFileName)
+
+	const extraRecords = 5
+	for i := 0; i < taskJournalCompactRecords+extraRecords; i++ {
+		state.UpdateObjectState(i%2, TaskObjectStatusFailed, fmt.Sprintf("attempt %d", i))
+	}
+
+	// the journal has been compacted once, only the records after the compaction remain in it
+	journal, err := os.ReadFile(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(journal, []byte("\n")); lines != extraRecords {
+		t.Errorf("got %d records in the journal, expected %d", lines, extraRecords)
+	}
+	snapshot, err := os.ReadFile(filepath.Join(taskDir, taskSnapshotFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshotState TaskState
+	if err = json.Unmarshal(snapshot, &snapshotState); err != nil {
+		t.Fatal(err)
+	}
+	if snapshotState.JournalSeq != taskJournalCompactRecords {
+		t.Errorf("got the seq %d of the snapshot, expected %d", snapshotState.JournalSeq, taskJournalCompactRecords)
+	}
+
+	// the state loaded without closing the journal is the same as the state after a crash
+	loaded, err := loadTaskState(homeDir, state.TaskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastAttempt := taskJournalCompactRecords + extraRecords - 1
+	if loaded.JournalSeq != uint64(lastAttempt+1) || loaded.ObjectState[lastAttempt%2].Comment != fmt.Sprintf("attempt %d", lastAttempt) {
+		t.Errorf("got the seq %d and the comment %q, expected the last attempt %d",
+			loaded.JournalSeq, loaded.ObjectState[lastAttempt%2].Comment, lastAttempt)
+	}
+
+	state.SetStatus(TaskStatusFail)
+	if err = state.closeJournal(); err != nil {
+		t.Fatal(err)
+	}
+	if journal, err = os.ReadFile(journalPath); err != nil || len(journal) != 0 {
+		t.Errorf("got the journal %q (%v) after closing, expected it to be compacted", journal, err)
+	}
+	if loaded, err = loadTaskState(homeDir, state.TaskID); err != nil || loaded.Status != TaskStatusFail {
+		t.Errorf("got the task %+v (%v) after closing, expected the status %s", loaded, err, TaskStatusFail)
+	}
+	if _, err = os.Stat(filepath.Join(taskDir, taskSnapshotFileName+".tmp")); !os.IsNotExist(err) {
+		t.Errorf("the temp snapshot is left: %v", err)
+	}
+}
+
+func TestTaskJournalConcurrentCompaction(t *testing.T) {
+	homeDir := t.TempDir()
+	state := newTestTaskState("task")
+	if err := state.openJournal(homeDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// the records appended by the other routines while the snapshot is written must not be lost
+	const routines, updates = 4, taskJournalCompactRecords
+	var wg sync.WaitGroup
+	for r := 0; r < routines; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			for i := 0; i < updates; i++ {
+				state.UpdateObjectState(r%2, TaskObjectStatusFailed, fmt.Sprintf("routine %d attempt %d", r, i))
+			}
+		}(r)
+	}
+	wg.Wait()
+
+	loaded, err := loadTaskState(homeDir, state.TaskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.JournalSeq != routines*updates {
+		t.Errorf("got the seq %d, expected %d", loaded.JournalSeq, routines*updates)
+	}
+	for index, object := range state.ObjectState {
+		if loaded.ObjectState[index].Comment != object.Comment {
+			t.Errorf("got the comment %q of the object %d, expected %q", loaded.ObjectState[index].Comment, index, object.Comment)
+		}
+	}
+
+	journal, err := os.ReadFile(filepath.Join(getTaskDir(homeDir, state.TaskID), taskJournalFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(journal, []byte("\n")); lines >= routines*updates {
+		t.Errorf("got %d records in the journal, expected it to be compacted", lines)
+	}
+	if err = state.closeJournal(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// defaultTimezone is the timezone of the times printed by the former versions, it is kept as the default so that
+	// the printed times are not changed for the existing users, use the local timezone by "--timezone local"
+	defaultTimezone = "Asia/Shanghai"
+	localTimezone   = "local"
+	utcTimezone     = "utc"
+	// the names of the builtin time formats, other values of the time format flag are used as go time layouts
+	iso8601TimeFormat = "iso8601"
+	rfc3339TimeFormat = "rfc3339"
+)
+
+var (
+	// displayLocation is the timezone of the printed times and the times entered by the user without a zone
+	displayLocation = loadDefaultLocation()
+	// displayTimeLayout is the layout of the printed times in table mode
+	displayTimeLayout = iso8601DateFormat
+)
+
+var unixTimestampRegexp = regexp.MustCompile(`^[0-9]+$`)
+
+// initTimeFormat set the timezone and the layout of the times, the timezone can be local, UTC or an IANA name
+// like Europe/Berlin, it is Asia/Shanghai by default, the format can be iso8601, rfc3339 or a go time layout like "02 Jan 06 15:04 MST"
+func initTimeFormat(timezone, format string) error {
+	location, err := parseTimezone(timezone)
+	if err != nil {
+		return err
+	}
+	layout, err := parseTimeLayout(format)
+	if err != nil {
+		return err
+	}
+	displayLocation, displayTimeLayout = location, layout
+	return nil
+}
+
+// loadDefaultLocation return the location of the default timezone, the fixed zone of the same offset is used if the
+// timezone database is not installed, Asia/Shanghai has no daylight saving time
+func loadDefaultLocation() *time.Location {
+	location, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		return time.FixedZone("CST", 8*60*60)
+	}
+	return location
+}
+
+// parseTimezone return the location of the timezone flag, the empty timezone is the default timezone
+func parseTimezone(timezone string) (*time.Location, error) {
+	switch strings.ToLower(timezone) {
+	case "":
+		return loadDefaultLocation(), nil
+	case localTimezone:
+		return time.Local, nil
+	case utcTimezone:
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %s, it should be local, UTC or an IANA name like America/New_York: %v", timezone, err)
+	}
+	return location, nil
+}
+
+// parseTimeLayout return the go time layout of the time format flag
+func parseTimeLayout(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", iso8601TimeFormat:
+		return iso8601DateFormat, nil
+	case rfc3339TimeFormat:
+		return time.RFC3339, nil
+	}
+	// a layout without any reference time element is almost always a mistake
+	if time.Date(1999, 11, 28, 23, 59, 58, 0, time.UTC).Format(format) == format {
+		return "", fmt.Errorf("invalid time format %s, it should be iso8601, rfc3339 or a go time layout like \"2006-01-02 15:04\"", format)
+	}
+	return format, nil
+}
+
+// formatTime format the unix timestamp with the timezone and the layout of the time format flags
+func formatTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).In(displayLocation).Format(displayTimeLayout)
+}
+
+// formatRecordTime format the unix timestamp of the json and yaml records, the records always use RFC3339
+// so that they can be parsed by the programs, only the timezone flag is applied
+func formatRecordTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).In(displayLocation).Format(time.RFC3339)
+}
+
+// timeColumnWidth return the width of the time column of the tables
+func timeColumnWidth() int {
+	return len(formatTime(time.Now().Unix()))
+}
+
+// parseTimeInput parse the time entered by the user, it can be a unix timestamp, a time in the layout of the
+// time format flag or RFC3339. The times without a zone are in the timezone of the timezone flag.
+func parseTimeInput(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if unixTimestampRegexp.MatchString(value) {
+		timestamp, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(timestamp, 0), nil
+	}
+	if t, err := time.ParseInLocation(displayTimeLayout, value, displayLocation); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.RFC3339, value, displayLocation); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("fail to parse time %s, it should be a unix timestamp, RFC3339 or in the format of \"%s\"",
+		value, displayTimeLayout)
+}
+
+// getTimeFlag return the unix timestamp of the time flag entered by the user, 0 is returned if it is not set
+func getTimeFlag(ctx *cli.Context, flagName string) (int64, error) {
+	value := ctx.String(flagName)
+	if value == "" {
+		return 0, nil
+	}
+	t, err := parseTimeInput(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --%s: %v", flagName, err)
+	}
+	return t.Unix(), nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimezone(t *testing.T) {
+	tests := []struct {
+		timezone string
+		// offset is the offset of the timezone in seconds at the unix time 0, it is not checked for the local timezone
+		offset  int
+		local   bool
+		wantErr bool
+	}{
+		{timezone: "", offset: 8 * 60 * 60},
+		{timezone: defaultTimezone, offset: 8 * 60 * 60},
+		{timezone: "local", local: true},
+		{timezone: "LOCAL", local: true},
+		{timezone: "UTC", offset: 0},
+		{timezone: "utc", offset: 0},
+		{timezone: "America/New_York", offset: -5 * 60 * 60},
+		{timezone: "Mars/Olympus_Mons", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			location, err := parseTimezone(tt.timezone)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", location)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.local {
+				if location != time.Local {
+					t.Errorf("got %s, expected the local timezone", location)
+				}
+				return
+			}
+			if _, offset := time.Unix(0, 0).In(location).Zone(); offset != tt.offset {
+				t.Errorf("got the offset %d of %s, expected %d", offset, location, tt.offset)
+			}
+		})
+	}
+}
+
+func TestFormatAndParseTime(t *testing.T) {
+	oldLocation, oldLayout := displayLocation, displayTimeLayout
+	defer func() { displayLocation, displayTimeLayout = oldLocation, oldLayout }()
+
+	tests := []struct {
+		name       string
+		timezone   string
+		format     string
+		timestamp  int64
+		want       string
+		wantRecord string
+		wantErr    bool
+	}{
+		{name: "default", timestamp: 0, want: "1970-01-01 08:00:00", wantRecord: "1970-01-01T08:00:00+08:00"},
+		{name: "utc rfc3339", timezone: "UTC", format: "rfc3339", timestamp: 0, want: "1970-01-01T00:00:00Z", wantRecord: "1970-01-01T00:00:00Z"},
+		{
+			name: "layout", timezone: "UTC", format: "02 Jan 06 15:04 MST", timestamp: 1700000000,
+			want: "14 Nov 23 22:13 UTC", wantRecord: "2023-11-14T22:13:20Z",
+		},
+		{name: "layout without time", format: "date", wantErr: true},
+		{name: "invalid timezone", timezone: "Nowhere", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := initTimeFormat(tt.timezone, tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := formatTime(tt.timestamp); got != tt.want {
+				t.Errorf("got %s, expected %s", got, tt.want)
+			}
+			if got := formatRecordTime(tt.timestamp); got != tt.wantRecord {
+				t.Errorf("got the record time %s, expected %s", got, tt.wantRecord)
+			}
+		})
+	}
+}
+
+func TestParseTimeInput(t *testing.T) {
+	oldLocation, oldLayout := displayLocation, displayTimeLayout
+	defer func() { displayLocation, displayTimeLayout = oldLocation, oldLayout }()
+	if err := initTimeFormat("", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "1700000000", want: 1700000000},
+		{value: "1970-01-01 08:00:10", want: 10},
+		{value: " 1970-01-01 08:00:10 ", want: 10},
+		{value: "1970-01-01T00:00:10Z", want: 10},
+		{value: "1970-01-01T02:00:10+02:00", want: 10},
+		{value: "01/01/1970", wantErr: true},
+		{value: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseTimeInput(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Unix() != tt.want {
+				t.Errorf("got %d, expected %d", got.Unix(), tt.want)
+			}
+		})
+	}
+}
@@ -66,6 +66,41 @@ const (
 	IdFlag                  = "id"
 	DestChainIdFlag         = "destChainId"
 	taskIDFlag              = "taskId"
+	concurrencyFlag         = "concurrency"
+	deleteFlag              = "delete"
+	batchSizeFlag           = "batchSize"
+	verifyFlag              = "verify"
+	limitRateFlag           = "limit-rate"
+	errorFormatFlag         = "errorFormat"
+	outputFlag              = "output"
+	timezoneFlag            = "timezone"
+	timeFormatFlag          = "timeFormat"
+	profileFlag             = "profile"
+	networkFlag             = "network"
+	forceFlag               = "force"
+	sealTimeoutFlag         = "sealTimeout"
+
+	// the environment variables of the flags, the flags set in the command line take precedence over them
+	hostEnv             = "GNFD_HOST"
+	rpcAddrEnv          = "GNFD_RPC_ADDR"
+	chainIdEnv          = "GNFD_CHAIN_ID"
+	profileEnv          = "GNFD_PROFILE"
+	passwordFileEnv     = "GNFD_PASSWORD_FILE"
+	configEnv           = "GNFD_CONFIG"
+	keyStoreEnv         = "GNFD_KEYSTORE"
+	homeEnv             = "GNFD_HOME"
+	limitRateEnv        = "GNFD_LIMIT_RATE"
+	timezoneEnv         = "GNFD_TIMEZONE"
+	timeFormatEnv       = "GNFD_TIME_FORMAT"
+	outputEnv           = "GNFD_OUTPUT"
+	errorFormatEnv      = "GNFD_ERROR_FORMAT"
+	primarySPEnv        = "GNFD_PRIMARY_SP"
+	paymentAddressEnv   = "GNFD_PAYMENT_ADDRESS"
+	bucketVisibilityEnv = "GNFD_BUCKET_VISIBILITY"
+	objectVisibilityEnv = "GNFD_OBJECT_VISIBILITY"
+	partSizeEnv         = "GNFD_PART_SIZE"
+	concurrencyEnv      = "GNFD_CONCURRENCY"
+	sealTimeoutEnv      = "GNFD_SEAL_TIMEOUT"
 
 	ownerAddressFlag = "owner"
 	addressFlag      = "address"
@@ -73,17 +108,26 @@ const (
 	fromAddressFlag  = "fromAddress"
 	amountFlag       = "amount"
 
-	unsafeFlag       = "unsafe"
-	unarmoredFlag    = "unarmoredHex"
-	passwordFileFlag = "passwordfile"
-	formatFlag       = "format"
-	defaultFormat    = "plaintxt"
-	jsonFormat       = "json"
-	homeFlag         = "home"
-	keyStoreFlag     = "keystore"
-	configFlag       = "config"
-	EncryptScryptN   = 1 << 18
-	EncryptScryptP   = 1
+	unsafeFlag          = "unsafe"
+	mnemonicFlag        = "mnemonic"
+	hdPathFlag          = "hdPath"
+	accountIndexFlag    = "accountIndex"
+	kdfFlag             = "kdf"
+	newPasswordFileFlag = "newPasswordFile"
+	exportFileFlag      = "exportFile"
+	ttlFlag             = "ttl"
+	accountNameFlag     = "name"
+	yesFlag             = "yes"
+	unarmoredFlag       = "unarmoredHex"
+	passwordFileFlag    = "passwordfile"
+	formatFlag          = "format"
+	defaultFormat       = "plaintxt"
+	jsonFormat          = "json"
+	homeFlag            = "home"
+	keyStoreFlag        = "keystore"
+	configFlag          = "config"
+	EncryptScryptN      = 1 << 18
+	EncryptScryptP      = 1
 
 	ContextTimeout       = time.Second * 20
 	BucketResourcePrefix = "grn:b::"
@@ -97,7 +141,9 @@ const (
 	DefaultConfigPath  = "config/config.toml"
 	DefaultConfigDir   = ".gnfd-cmd"
 	DefaultAccountPath = "account/defaultKey"
-	DefaultKeyDir      = "keystore"
+	// DefaultAccountAliasPath is the json file of the account names, the names are mapped to the addresses
+	DefaultAccountAliasPath = "account/aliases.json"
+	DefaultKeyDir           = "keystore"
 
 	rpcAddrConfigField = "rpcAddr"
 	chainIdConfigField = "chainId"
@@ -118,8 +164,13 @@ const (
 	exitStatus         = "GRACEFUL_EXITING"
 	StatusSPrefix      = "STATUS_"
 	defaultMaxKey      = 500
+	// stdioFileArg is the file argument which means reading from stdin or writing to stdout
+	stdioFileArg = "-"
+
+	defaultDeleteBatchSize = 50
+	defaultCreateBatchSize = 20
+	defaultSealTimeout     = time.Hour
 
-	noBalanceErr           = "key not found"
 	maxListMemberNum       = 1000
 	progressDelayPrintSize = 10 * 1024 * 1024
 	timeFormat             = "2006-01-02T15-04-05.000000000Z"
@@ -133,22 +184,62 @@ const (
 	TaskStatusSuccess = "successful"
 
 	TaskObjectStatusWaitForUpload = "wait_for_upload"
-	TaskObjectStatusCreated       = "created"
-	TaskObjectStatusSeal          = "sealed"
-	TaskObjectStatusFailed        = "failed"
+	// TaskObjectStatusCreatedOnChain means the object has been created on chain and the payload is waiting for uploading
+	TaskObjectStatusCreatedOnChain = "created_on_chain"
+	TaskObjectStatusCreated        = "created"
+	TaskObjectStatusSeal           = "sealed"
+	TaskObjectStatusFailed         = "failed"
+	// TaskObjectStatusStale means the local file has been changed or deleted after the object was created on chain
+	TaskObjectStatusStale = "stale"
+
+	TaskObjectStatusWaitForDownload = "wait_for_download"
+	TaskObjectStatusDownloaded      = "downloaded"
+
+	TaskTypeUpload   = "upload"
+	TaskTypeDownload = "download"
 )
 
 var (
-	ErrBucketNotExist     = errors.New("bucket not exist")
-	ErrObjectNotExist     = errors.New("object not exist")
-	ErrObjectNotCreated   = errors.New("object not created on chain")
-	ErrObjectSeal         = errors.New("object not sealed before downloading")
-	ErrGroupNotExist      = errors.New("group not exist")
-	ErrFileNotExist       = errors.New("file path not exist")
+	ErrBucketNotExist   = errors.New("bucket not exist")
+	ErrObjectNotExist   = errors.New("object not exist")
+	ErrObjectNotCreated = errors.New("object not created on chain")
+	ErrObjectSeal       = errors.New("object not sealed before downloading")
+	ErrGroupNotExist    = errors.New("group not exist")
+	ErrFileNotExist     = errors.New("file path not exist")
+	// ErrTxnNotConfirmed means the txn has been submitted, but it is unknown whether it has been committed
+	ErrTxnNotConfirmed    = errors.New("txn not confirmed")
 	SyncBroadcastMode     = tx.BroadcastMode_BROADCAST_MODE_SYNC
 	TxnOptionWithSyncMode = types.TxOption{Mode: &SyncBroadcastMode}
 )
 
+// headObjectErr return ErrObjectNotExist if the object is not found on chain, the other errors of HeadObject
+// like the network errors are wrapped, so that they are not reported as the missing object
+func headObjectErr(bucketName, objectName string, err error) error {
+	if isNoSuchObjectErr(err) {
+		return ErrObjectNotExist
+	}
+	return fmt.Errorf("failed to query the object %s/%s: %w", bucketName, objectName, err)
+}
+
+// headBucketErr return ErrBucketNotExist if the bucket is not found on chain, the other errors of HeadBucket
+// like the network errors are wrapped, so that they are not reported as the missing bucket
+func headBucketErr(bucketName string, err error) error {
+	if isNoSuchBucketErr(err) {
+		return ErrBucketNotExist
+	}
+	return fmt.Errorf("failed to query the bucket %s: %w", bucketName, err)
+}
+
+// isNoSuchBucketErr check if the error of HeadBucket means the bucket is not found on chain
+func isNoSuchBucketErr(err error) bool {
+	return err != nil && strings.Contains(err.Error(), storageTypes.ErrNoSuchBucket.Error())
+}
+
+// isNoSuchObjectErr check if the error of HeadObject means the object is not found on chain
+func isNoSuchObjectErr(err error) bool {
+	return err != nil && strings.Contains(err.Error(), storageTypes.ErrNoSuchObject.Error())
+}
+
 // ClientOptions indicates the metadata to construct new greenfield client
 type ClientOptions struct {
 	// IsQueryCmd indicate whether the command is query command
@@ -195,32 +286,30 @@ func getVisibilityType(visibility string) (storageTypes.VisibilityType, error) {
 	}
 }
 
+// toCmdErr wrap the error of the command with the exit code, the error is printed by main before exiting
 func toCmdErr(err error) error {
-	if strings.Contains(err.Error(), noBalanceErr) {
-		fmt.Println("The operator account have no balance, please transfer token to your account")
-	} else {
-		fmt.Printf("run command error: %s\n", err.Error())
+	// avoid returning a nil *CmdError as a non-nil error
+	if err == nil {
+		return nil
 	}
-	return nil
+	return classifyCmdErr(err)
 }
 
 // parse object info meta on the chain
 func parseObjectInfo(objectDetail *sdktypes.ObjectDetail) {
 	info := objectDetail.ObjectInfo.String()
-	fmt.Println("object_status:", objectDetail.ObjectInfo.ObjectStatus)
+	fmt.Fprintln(infoWriter, "object_status:", objectDetail.ObjectInfo.ObjectStatus)
 	infoStr := strings.Split(info, " ")
 	checksumID := 0
 	for _, objInfo := range infoStr {
 		if strings.Contains(objInfo, "create_at:") {
 			timeInfo := strings.Split(objInfo, ":")
 			timestamp, _ := strconv.ParseInt(timeInfo[1], 10, 64)
-			location, _ := time.LoadLocation("Asia/Shanghai")
-			t := time.Unix(timestamp, 0).In(location)
-			objInfo = timeInfo[0] + ":" + t.Format(iso8601DateFormat)
+			objInfo = timeInfo[0] + ":" + formatTime(timestamp)
 		}
 		if strings.Contains(objInfo, "checksums:") {
 			if checksumID == 0 {
-				fmt.Println("checksums:")
+				fmt.Fprintln(infoWriter, "checksums:")
 			}
 			hashInfo := strings.Split(objInfo, ":")
 			objInfo = hashInfo[0] + "[" + strconv.Itoa(checksumID) + "]" + ":" + hex.EncodeToString([]byte(hashInfo[1]))
@@ -229,7 +318,7 @@ func parseObjectInfo(objectDetail *sdktypes.ObjectDetail) {
 		if strings.Contains(objInfo, "status") {
 			continue
 		}
-		fmt.Println(objInfo)
+		fmt.Fprintln(infoWriter, objInfo)
 	}
 }
 
@@ -244,24 +333,22 @@ func getJsonMarshaler() *jsonpb.Marshaler {
 func parseByJsonFormat(v proto.Message) {
 	jsonData, err := getJsonMarshaler().MarshalToString(v)
 	if err != nil {
-		fmt.Println("Error marshalling to JSON:", err)
+		fmt.Fprintln(infoWriter, "Error marshalling to JSON:", err)
 		return
 	}
-	fmt.Println(jsonData)
+	fmt.Fprintln(infoWriter, jsonData)
 }
 
 func parseBucketInfo(info *storageTypes.BucketInfo) {
-	fmt.Println("bucket_status:", info.BucketStatus.String())
+	fmt.Fprintln(infoWriter, "bucket_status:", info.BucketStatus.String())
 	infoStr := strings.Split(info.String(), " ")
 	for _, bucketInfo := range infoStr {
 		if strings.Contains(bucketInfo, "create_at:") {
 			timeInfo := strings.Split(bucketInfo, ":")
 			timestamp, _ := strconv.ParseInt(timeInfo[1], 10, 64)
-			location, _ := time.LoadLocation("Asia/Shanghai")
-			t := time.Unix(timestamp, 0).In(location)
-			bucketInfo = timeInfo[0] + ":" + t.Format(iso8601DateFormat)
+			bucketInfo = timeInfo[0] + ":" + formatTime(timestamp)
 		}
-		fmt.Println(bucketInfo)
+		fmt.Fprintln(infoWriter, bucketInfo)
 	}
 }
 
@@ -419,18 +506,19 @@ func getPassword(ctx *cli.Context, needNotice bool) (string, error) {
 		return strings.TrimRight(string(readContent), "\r\n"), nil
 	}
 
-	fmt.Print("Please enter the passphrase now:")
+	// print the prompt to stderr, so that it will not be mixed with the object payload written to stdout
+	fmt.Fprint(os.Stderr, "Please enter the passphrase now:")
 
 	bytePassword, err := term.ReadPassword(int(os.Stdin.Fd()))
 	if err != nil {
-		fmt.Println("read password err:", err)
+		fmt.Fprintln(os.Stderr, "read password err:", err)
 		return "", err
 	}
 	password := string(bytePassword)
-	fmt.Println()
+	fmt.Fprintln(os.Stderr)
 	if needNotice {
-		fmt.Println("- You must BACKUP your key file! Without the key, it's impossible to set transaction to greenfield!")
-		fmt.Println("- You must REMEMBER your password! Without the password, it's impossible to decrypt the key!")
+		fmt.Fprintln(infoWriter, "- You must BACKUP your key file! Without the key, it's impossible to set transaction to greenfield!")
+		fmt.Fprintln(infoWriter, "- You must REMEMBER your password! Without the password, it's impossible to decrypt the key!")
 	}
 	return password, nil
 }
@@ -477,6 +565,9 @@ type cmdConfig struct {
 	RpcAddr string `toml:"rpcAddr"`
 	ChainId string `toml:"chainId"`
 	Host    string `toml:"host"`
+	// Profile is the name of the profile in use, it is set by "config use-profile"
+	Profile  string                     `toml:"profile"`
+	Profiles map[string]*networkProfile `toml:"profiles"`
 }
 
 // parseConfigFile decode the config file of TOML format
@@ -488,9 +579,19 @@ func parseConfigFile(filePath string) (*cmdConfig, error) {
 	return &config, nil
 }
 
-const configContent = "rpcAddr = \"https://greenfield-chain.bnbchain.org:443\"\nchainId = \"greenfield_1017-1\""
+// getConfigPath return the path of the config file set by the config flag or the default path under the home dir
+func getConfigPath(ctx *cli.Context) (string, error) {
+	if configFile := ctx.String(configFlag); configFile != "" {
+		return configFile, nil
+	}
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, DefaultConfigPath), nil
+}
 
-// loadConfig parse the default config file path
+// loadConfig parse the config file of the default path, the file is created by "gnfd-cmd config init"
 func loadConfig(ctx *cli.Context) (*cmdConfig, error) {
 	homeDir, err := getHomeDir(ctx)
 	if err != nil {
@@ -498,18 +599,10 @@ func loadConfig(ctx *cli.Context) (*cmdConfig, error) {
 	}
 	configPath := filepath.Join(homeDir, DefaultConfigPath)
 
-	// if config default path not exist, create the config file with default test net config
 	_, err = os.Stat(configPath)
 	if os.IsNotExist(err) {
-		if err = os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
-			return nil, toCmdErr(errors.New("failed to create config file directory :%s" + filepath.Dir(configPath)))
-		}
-
-		err = os.WriteFile(configPath, []byte(configContent), 0644)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create config file: %v", err)
-		}
-		fmt.Println("generate default config file on path:", configPath)
+		return nil, newCmdError(exitCodeUsage, fmt.Errorf("the config file %s does not exist, run \"gnfd-cmd config init\" to create it, "+
+			"or set the config file by --%s", configPath, configFlag))
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to check config file: %v", err)
 	}
@@ -522,12 +615,26 @@ func loadConfig(ctx *cli.Context) (*cmdConfig, error) {
 	return content, nil
 }
 
-// getConfig parse the config of the client, return rpc address, chainId and host
+// isDefaultConfigExist check if the config file of the default path exists, the errors other than the missing file
+// are reported by loadConfig
+func isDefaultConfigExist(ctx *cli.Context) bool {
+	homeDir, err := getHomeDir(ctx)
+	if err != nil {
+		return true
+	}
+	_, err = os.Stat(filepath.Join(homeDir, DefaultConfigPath))
+	return !os.IsNotExist(err)
+}
+
+// getConfig parse the config of the client, return rpc address, chainId and host.
+// The values of the flags take precedence over the profile in use, and the profile takes precedence over
+// the top-level values of the config file.
 func getConfig(ctx *cli.Context) (string, string, string, error) {
 	rpcAddr := ctx.String(rpcAddrConfigField)
 	chainId := ctx.String(chainIdConfigField)
-	if rpcAddr != "" && chainId != "" {
-		return rpcAddr, chainId, ctx.String(hostConfigField), nil
+	host := ctx.String(hostConfigField)
+	if rpcAddr != "" && chainId != "" && ctx.String(profileFlag) == "" {
+		return rpcAddr, chainId, host, nil
 	}
 
 	configFile := ctx.String("config")
@@ -539,50 +646,96 @@ func getConfig(ctx *cli.Context) (string, string, string, error) {
 		if err != nil {
 			return "", "", "", err
 		}
+	} else if ctx.String(profileFlag) != "" && !isDefaultConfigExist(ctx) {
+		// the built-in presets can be used without the config file
+		config = &cmdConfig{}
 	} else {
-		// if file exist in config default path, read default file.
-		// else generate the default file for user in the default path
+		// read the config file of the default path which is created by "gnfd-cmd config init"
 		config, err = loadConfig(ctx)
 		if err != nil {
 			return "", "", "", err
 		}
 	}
 
-	if config.RpcAddr == "" || config.ChainId == "" {
+	network := &networkProfile{RpcAddr: config.RpcAddr, ChainId: config.ChainId, Host: config.Host}
+	profileName := getProfileName(ctx, config)
+	if profileName != "" {
+		if network, err = config.getProfile(profileName); err != nil {
+			return "", "", "", err
+		}
+	}
+	if rpcAddr != "" {
+		network.RpcAddr = rpcAddr
+	}
+	if chainId != "" {
+		network.ChainId = chainId
+	}
+	if host != "" {
+		network.Host = host
+	}
+
+	if network.RpcAddr == "" || network.ChainId == "" {
 		return "", "", "", fmt.Errorf("failed to parse rpc address or chain id , please set it in the config file")
 	}
 
-	return config.RpcAddr, config.ChainId, config.Host, nil
+	return network.RpcAddr, network.ChainId, network.Host, nil
 }
 
 func loadKeyStoreFile(ctx *cli.Context) ([]byte, string, error) {
 	keyfilePath := ctx.String("keystore")
+	var address string
+	// the keystore flag can also be the name or the address of an account in the home dir
+	if keyfilePath != "" {
+		if _, err := os.Stat(keyfilePath); os.IsNotExist(err) {
+			if address, err = resolveAccountAddress(ctx, keyfilePath); err != nil {
+				return nil, "", newCmdError(exitCodeNotFound, fmt.Errorf("the keystore %s does not exist, %v", keyfilePath, err))
+			}
+			keyfilePath = ""
+		}
+	}
 	if keyfilePath == "" {
 		homeDir, err := getHomeDir(ctx)
 		if err != nil {
 			return nil, "", err
 		}
 
-		defaultAddrFilePath := filepath.Join(homeDir, DefaultAccountPath)
-		fileContent, err := os.ReadFile(defaultAddrFilePath)
-		if err != nil {
-			return nil, "", fmt.Errorf("invalid default address" + err.Error())
+		// the account of the profile in use takes precedence over the default account
+		if address == "" {
+			if address, err = getProfileAccount(ctx); err != nil {
+				return nil, "", err
+			}
+			if address != "" {
+				if address, err = resolveAccountAddress(ctx, address); err != nil {
+					return nil, "", newCmdError(exitCodeAuth, fmt.Errorf("invalid account of the profile: %v", err))
+				}
+			}
 		}
-		if len(fileContent) != accountAddressLen {
-			return nil, "", fmt.Errorf("invalid default address length")
+		if address == "" {
+			defaultAddrFilePath := filepath.Join(homeDir, DefaultAccountPath)
+			fileContent, err := os.ReadFile(defaultAddrFilePath)
+			if err != nil {
+				return nil, "", newCmdError(exitCodeAuth, fmt.Errorf("invalid default address"+err.Error()))
+			}
+			if len(fileContent) != accountAddressLen {
+				return nil, "", newCmdError(exitCodeAuth, fmt.Errorf("invalid default address length"))
+			}
+			address = string(fileContent)
 		}
 		// get the default keystore file path
 		keyStorePath := filepath.Join(homeDir, DefaultKeyDir)
-		keyfilePath, err = getKeystoreFileByAddress(keyStorePath, string(fileContent))
+		keyfilePath, err = getKeystoreFileByAddress(keyStorePath, convertAddressToLower(address))
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to load the default keystore:" + err.Error())
+			return nil, "", newCmdError(exitCodeAuth, fmt.Errorf("failed to load the default keystore:"+err.Error()))
+		}
+		if keyfilePath == "" {
+			return nil, "", newCmdError(exitCodeNotFound, fmt.Errorf("the keystore of the account %s does not exist in %s", address, keyStorePath))
 		}
 	}
 
 	// fetch private key from keystore
 	content, err := os.ReadFile(keyfilePath)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read the keyfile at '%s': %v \n", keyfilePath, err)
+		return nil, "", newCmdError(exitCodeAuth, fmt.Errorf("failed to read the keyfile at '%s': %v \n", keyfilePath, err))
 	}
 
 	return content, keyfilePath, nil
@@ -620,8 +773,10 @@ func getHomeDir(ctx *cli.Context) (string, error) {
 
 func getUserAddress(ctx *cli.Context) (string, error) {
 	var userAddress string
-	var err error
-	flagAddr := ctx.String(addressFlag)
+	flagAddr, err := getAddressFlag(ctx, addressFlag)
+	if err != nil {
+		return "", toCmdErr(err)
+	}
 	if flagAddr != "" {
 		_, err = sdk.AccAddressFromHexUnsafe(flagAddr)
 		if err != nil {
@@ -656,6 +811,28 @@ func parseFileByArg(ctx *cli.Context, argIndex int) (int64, error) {
 	return objectSize, nil
 }
 
+// spoolStdinToTempFile write the data read from stdin to a temp file, the integrity hash of the payload
+// need to be computed before creating the object, so the payload can not be streamed directly.
+// The caller should remove the temp file after using it.
+func spoolStdinToTempFile() (string, int64, error) {
+	tempFile, err := os.CreateTemp("", "gnfd-stdin-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer tempFile.Close()
+
+	// read one more byte than the max file size to find out whether the payload is too large
+	size, err := io.Copy(tempFile, io.LimitReader(os.Stdin, int64(maxFileSize)+1))
+	if err == nil && size > int64(maxFileSize) {
+		err = fmt.Errorf("the data of stdin is larger than %d bytes", int64(maxFileSize))
+	}
+	if err != nil {
+		os.Remove(tempFile.Name())
+		return "", 0, err
+	}
+	return tempFile.Name(), size, nil
+}
+
 type ProgressReader struct {
 	io.Reader
 	Total          int64
@@ -682,9 +859,9 @@ func (pr *ProgressReader) printProgress() {
 		progressStr := fmt.Sprintf("uploading progress: %.2f%% [ %s / %s ], rate: %s    ",
 			progress, getConvertSize(pr.Current), getConvertSize(pr.Total), getConvertRate(uploadSpeed))
 		// Clear current line
-		fmt.Print("\r", strings.Repeat(" ", len(pr.LastPrintedStr)), "\r")
+		fmt.Fprint(infoWriter, "\r", strings.Repeat(" ", len(pr.LastPrintedStr)), "\r")
 		// Print new progress
-		fmt.Print(progressStr)
+		fmt.Fprint(infoWriter, progressStr)
 
 		pr.LastPrinted = now
 	}
@@ -714,7 +891,7 @@ func (pw *ProgressWriter) printProgress() {
 	downloadSpeed := float64(downloadedBytes) / elapsed.Seconds()
 
 	if now.Sub(pw.LastPrinted) >= printRateInterval { // print rate every half second
-		fmt.Printf("\rdownloding progress: %.2f%% [ %s / %s ], rate: %s    ",
+		fmt.Fprintf(infoWriter, "\rdownloding progress: %.2f%% [ %s / %s ], rate: %s    ",
 			progress, getConvertSize(pw.Current), getConvertSize(pw.Total), getConvertRate(downloadSpeed))
 		pw.LastPrinted = now
 	}
@@ -831,24 +1008,79 @@ type TaskState struct {
 	BucketName  string                    `json:"bucket_name"`
 	FolderName  string                    `json:"folder_name"`
 	Flag        UploadFlag                `json:"flag"`
+	CreateTime  int64                     `json:"create_time,omitempty"`
+	// Type is the type of the task, the task created by the former version without type is an upload task
+	Type string `json:"type,omitempty"`
+	// Prefix and Verify are only used by the download task, the FolderName of it is the local directory
+	Prefix string `json:"prefix,omitempty"`
+	Verify bool   `json:"verify,omitempty"`
+	// JournalSeq is the seq of the last journal record applied to the state
+	JournalSeq uint64 `json:"journal_seq,omitempty"`
+
+	journal *taskJournal
+}
+
+// IsDownload check if the task is a download task
+func (t *TaskState) IsDownload() bool {
+	return t.Type == TaskTypeDownload
 }
 
+// UploadTaskObject is the object of the task, for the download task, FilePath is the local path to download to
 type UploadTaskObject struct {
-	BucketName         string `json:"bucket_name"`
-	ObjectName         string `json:"object_name"`
-	FilePath           string `json:"file_path"`
-	UploadSingleFolder bool   `json:"upload_single_folder"`
-	ObjectSize         int64  `json:"object_size"`
-	Status             string `json:"status"`
-	Comment            string `json:"comment"`
+	BucketName string `json:"bucket_name"`
+	ObjectName string `json:"object_name"`
+	FilePath   string `json:"file_path"`
+	// IsFolder means the object is a folder, it is only created on chain by the upload task and only creates the
+	// local directory by the download task. The json name is kept to load the tasks of the former version.
+	IsFolder      bool   `json:"upload_single_folder"`
+	ObjectSize    int64  `json:"object_size"`
+	Status        string `json:"status"`
+	Comment       string `json:"comment"`
+	CreateTxnHash string `json:"create_txn_hash,omitempty"`
+	// ModTime and Fingerprint are used to detect the changes of the local file when retrying the upload task
+	ModTime     int64  `json:"mod_time,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 func (t *TaskState) UpdateObjectState(index int, status, comment string) {
-	_, ok := t.ObjectState[index]
-	if ok {
-		t.Lock.Lock()
-		t.ObjectState[index].Status = status
-		t.ObjectState[index].Comment = comment
-		t.Lock.Unlock()
+	var seq uint64
+	t.Lock.Lock()
+	if object, ok := t.ObjectState[index]; ok {
+		object.Status = status
+		object.Comment = comment
+		seq = t.appendJournalLocked(index)
 	}
+	t.Lock.Unlock()
+	t.syncJournal(seq)
+}
+
+// SetCreateTxnHash record the hash of the txn which creates the object on chain
+func (t *TaskState) SetCreateTxnHash(index int, txnHash string) {
+	var seq uint64
+	t.Lock.Lock()
+	if object, ok := t.ObjectState[index]; ok {
+		object.CreateTxnHash = txnHash
+		seq = t.appendJournalLocked(index)
+	}
+	t.Lock.Unlock()
+	t.syncJournal(seq)
+}
+
+// GetObjectStatus return the status of the object, it is safe to be called by concurrent uploading routines
+func (t *TaskState) GetObjectStatus(index int) string {
+	t.Lock.Lock()
+	defer t.Lock.Unlock()
+	if object, ok := t.ObjectState[index]; ok {
+		return object.Status
+	}
+	return ""
+}
+
+// SetStatus update the status of the whole task
+func (t *TaskState) SetStatus(status string) {
+	t.Lock.Lock()
+	t.Status = status
+	seq := t.appendJournalLocked(taskJournalIndexOfTask)
+	t.Lock.Unlock()
+	t.syncJournal(seq)
 }
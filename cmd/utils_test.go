@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHeadBucketErr(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		notFound bool
+		code     int
+	}{
+		{name: "no such bucket", err: errors.New("rpc error: code = Unknown desc = No such bucket: unknown request"), notFound: true, code: exitCodeNotFound},
+		{name: "connection refused", err: errors.New("dial tcp 127.0.0.1:26750: connect: connection refused"), code: exitCodeNetworkError},
+		{name: "unknown", err: errors.New("something went wrong"), code: exitCodeGeneral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := headBucketErr("bucket", tt.err)
+			if errors.Is(err, ErrBucketNotExist) != tt.notFound {
+				t.Errorf("got %v, expected the bucket not found %v", err, tt.notFound)
+			}
+			if code := classifyCmdErr(err).Code; code != tt.code {
+				t.Errorf("got the exit code %d, expected %d", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestSpoolStdinToTempFile(t *testing.T) {
+	oldStdin := os.Stdin
+	defer func() { os.Stdin = oldStdin }()
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "empty", content: []byte{}},
+		{name: "data", content: bytes.Repeat([]byte("0123456789"), 100000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdinPath := filepath.Join(t.TempDir(), "stdin")
+			if err := os.WriteFile(stdinPath, tt.content, 0600); err != nil {
+				t.Fatal(err)
+			}
+			stdin, err := os.Open(stdinPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stdin.Close()
+			os.Stdin = stdin
+
+			tempFilePath, size, err := spoolStdinToTempFile()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer os.Remove(tempFilePath)
+			if size != int64(len(tt.content)) {
+				t.Errorf("got the size %d, expected %d", size, len(tt.content))
+			}
+			spooled, err := os.ReadFile(tempFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(spooled, tt.content) {
+				t.Errorf("the content of the temp file is not the data of stdin")
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/eth/ethsecp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+const (
+	// hexKeyFormat is the plain hex private key file imported by "account import"
+	hexKeyFormat = "hex"
+	// armoredKeyFormat is the cipher text of the keystore exported by "account export"
+	armoredKeyFormat = "armored"
+	// web3V3KeyFormat is the Web3 Secret Storage v3 file used by geth and MetaMask, the raw bytes of the
+	// private key are encrypted, while the keystore of gnfd-cmd encrypts the hex string of the private key
+	web3V3KeyFormat = "web3-v3"
+)
+
+// decryptWeb3Keystore decrypt the Web3 Secret Storage v3 file, the address is derived from the raw secp256k1 key
+// and it should match the address of the file if the file has one
+func decryptWeb3Keystore(keyJson []byte, password string) (*Key, error) {
+	var header struct {
+		Address string `json:"address"`
+		Version int    `json:"version"`
+	}
+	if err := json.Unmarshal(keyJson, &header); err != nil {
+		return nil, fmt.Errorf("invalid web3 keystore: %v", err)
+	}
+	if header.Version != 3 {
+		return nil, fmt.Errorf("invalid web3 keystore: the version is %d, only version 3 is supported", header.Version)
+	}
+
+	web3Key, err := keystore.DecryptKey(keyJson, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the web3 keystore: %v", err)
+	}
+	keyBytes := crypto.FromECDSA(web3Key.PrivateKey)
+	priKey := hd.EthSecp256k1.Generate()(keyBytes).(*ethsecp256k1.PrivKey)
+	address := sdk.AccAddress(priKey.PubKey().Address())
+
+	if header.Address != "" && common.HexToAddress(header.Address) != common.BytesToAddress(address.Bytes()) {
+		return nil, fmt.Errorf("the address of the web3 keystore %s does not match the address %s derived from the key",
+			header.Address, address.String())
+	}
+	return &Key{
+		Address:    address,
+		PrivateKey: hex.EncodeToString(keyBytes),
+	}, nil
+}
+
+// encryptWeb3Keystore encrypt the key into a Web3 Secret Storage v3 file which can be read by geth
+func encryptWeb3Keystore(key *Key, password string, scryptN, scryptP int) ([]byte, error) {
+	privateKey, err := crypto.HexToECDSA(key.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	return keystore.EncryptKey(&keystore.Key{
+		Id:         id,
+		Address:    common.BytesToAddress(key.Address.Bytes()),
+		PrivateKey: privateKey,
+	}, password, scryptN, scryptP)
+}
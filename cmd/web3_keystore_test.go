@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// gethKeystore is the keystore "aaa" in the testdata of the keystore package of geth, its password is "foobar"
+const (
+	gethKeystore         = `{"address":"f466859ead1932d743d622cb74fc058882e8648a","crypto":{"cipher":"aes-128-ctr","ciphertext":"cb664472deacb41a2e995fa7f96fe29ce744471deb8d146a0e43c7898c9ddd4d","cipherparams":{"iv":"dfd9ee70812add5f4b8f89d0811c9158"},"kdf":"scrypt","kdfparams":{"dklen":32,"n":8,"p":16,"r":8,"salt":"0d6769bf016d45c479213990d6a08d938469c4adad8a02ce507b4a4e7b7739f1"},"mac":"bac9af994b15a45dd39669fc66f9aa8a3b9dd8c22cb16e4d8d7ea089d0f1a1a9"},"id":"472e8b3d-afb6-45b5-8111-72c89895099a","version":3}`
+	gethKeystorePassword = "foobar"
+	gethKeystoreAddress  = "0xf466859ead1932d743d622cb74fc058882e8648a"
+)
+
+// setKeystoreField return the keystore with the top-level field replaced
+func setKeystoreField(t *testing.T, keyJson, field string, value interface{}) []byte {
+	content := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(keyJson), &content); err != nil {
+		t.Fatal(err)
+	}
+	if value == nil {
+		delete(content, field)
+	} else {
+		content[field] = value
+	}
+	result, err := json.Marshal(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestDecryptWeb3Keystore(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyJson  []byte
+		password string
+		wantErr  string
+	}{
+		{name: "geth keystore", keyJson: []byte(gethKeystore), password: gethKeystorePassword},
+		{name: "without address", keyJson: setKeystoreField(t, gethKeystore, "address", nil), password: gethKeystorePassword},
+		{name: "wrong password", keyJson: []byte(gethKeystore), password: "wrong", wantErr: "failed to decrypt"},
+		{
+			name:     "address mismatch",
+			keyJson:  setKeystoreField(t, gethKeystore, "address", "7ef5a6135f1fd6a02593eedc869c6d41d934aef8"),
+			password: gethKeystorePassword,
+			wantErr:  "does not match",
+		},
+		{name: "version 1", keyJson: setKeystoreField(t, gethKeystore, "version", 1), password: gethKeystorePassword, wantErr: "only version 3"},
+		{name: "not json", keyJson: []byte("garbage"), password: gethKeystorePassword, wantErr: "invalid web3 keystore"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := decryptWeb3Keystore(tt.keyJson, tt.password)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected the error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.EqualFold(key.Address.String(), gethKeystoreAddress) {
+				t.Errorf("got the address %s, expected %s", key.Address.String(), gethKeystoreAddress)
+			}
+		})
+	}
+}
+
+func TestEncryptWeb3KeystoreRoundTrip(t *testing.T) {
+	key, err := deriveKeyFromMnemonic(testMnemonic, defaultHDPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const password = "password"
+	keyJson, err := encryptWeb3Keystore(key, password, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := decryptWeb3Keystore(keyJson, password)
+	if err != nil {
+		t.Fatalf("failed to decrypt the exported keystore: %v", err)
+	}
+	if !decrypted.Address.Equals(key.Address) || decrypted.PrivateKey != key.PrivateKey {
+		t.Errorf("got the key of %s, expected %s", decrypted.Address.String(), key.Address.String())
+	}
+
+	// the exported keystore should also be read by geth
+	gethKey, err := keystore.DecryptKey(keyJson, password)
+	if err != nil {
+		t.Fatalf("geth failed to decrypt the exported keystore: %v", err)
+	}
+	if gethKey.Address != common.BytesToAddress(key.Address.Bytes()) {
+		t.Errorf("geth got the address %s, expected %s", gethKey.Address.Hex(), key.Address.String())
+	}
+
+	if _, err = decryptWeb3Keystore(keyJson, "wrong"); err == nil {
+		t.Error("expected an error of the wrong password")
+	}
+}
+
+func TestEncryptWeb3KeystoreInvalidKey(t *testing.T) {
+	key := &Key{Address: sdk.AccAddress(make([]byte, 20)), PrivateKey: "not hex"}
+	if _, err := encryptWeb3Keystore(key, "password", keystore.LightScryptN, keystore.LightScryptP); err == nil {
+		t.Error("expected an error of the invalid private key")
+	}
+}
+
+func TestWriteNewFile(t *testing.T) {
+	dir := t.TempDir()
+	existingFile := filepath.Join(dir, "existing.json")
+	if err := os.WriteFile(existingFile, []byte("existing"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filePath string
+		wantErr  string
+		want     string
+	}{
+		{name: "new file", filePath: filepath.Join(dir, "new.json"), want: "content"},
+		{name: "existing file", filePath: existingFile, wantErr: "already exists", want: "existing"},
+		{name: "missing dir", filePath: filepath.Join(dir, "missing", "new.json"), wantErr: "failed to create"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := writeNewFile(tt.filePath, []byte("content"))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got the error %v, expected %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == "" {
+				return
+			}
+			content, err := os.ReadFile(tt.filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("got the content %q, expected %q", content, tt.want)
+			}
+			if stat, _ := os.Stat(tt.filePath); tt.wantErr == "" && stat.Mode().Perm() != 0600 {
+				t.Errorf("got the mode %s, expected 0600", stat.Mode().Perm())
+			}
+		})
+	}
+}